@@ -2,86 +2,160 @@ package analyze
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"strings"
-	"time"
 
 	"distroanalyzer/profile"
+	"distroanalyzer/taxonomy"
 	"github.com/sashabaranov/go-openai"
 )
 
-// AIAnalyzer usa Cerebras (vía OpenAI SDK) para extraer señales.
+// AIAnalyzer usa un LLM compatible con la API de OpenAI para extraer señales.
 type AIAnalyzer struct {
-	client *openai.Client
-	model  string
+	client   *openai.Client
+	provider ProviderConfig
 }
 
-// NewAIAnalyzer crea un analizador configurado para la API de Cerebras.
-func NewAIAnalyzer(apiKey, model string) (*AIAnalyzer, error) {
-	if apiKey == "" {
-		return nil, fmt.Errorf("CEREBRAS_API_KEY is required")
+// NewAIAnalyzer crea un analizador para el proveedor configurado.
+func NewAIAnalyzer(provider ProviderConfig) (*AIAnalyzer, error) {
+	if err := provider.Validate(); err != nil {
+		return nil, err
 	}
 
-	// Configuramos el cliente para que apunte a Cerebras en lugar de OpenAI
-	config := openai.DefaultConfig(apiKey)
-	config.BaseURL = "https://api.cerebras.ai/v1"
+	// El SDK de OpenAI sirve para cualquier servidor compatible
+	config := openai.DefaultConfig(provider.APIKey)
+	config.BaseURL = provider.BaseURL
 
 	client := openai.NewClientWithConfig(config)
 
 	return &AIAnalyzer{
-		client: client,
-		model:  model,
+		client:   client,
+		provider: provider,
 	}, nil
 }
 
-// Analyze envía datos a Cerebras y parsea la respuesta.
-func (a *AIAnalyzer) Analyze(data *profile.RawData) (*profile.Signals, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
+// Analyze envía datos al LLM y valida la respuesta contra el esquema de
+// señales. Si no lo cumple, le devuelve los errores al modelo y le pide que
+// la corrija, hasta MaxRepairs veces.
+func (a *AIAnalyzer) Analyze(ctx context.Context, data *profile.RawData) (*profile.Signals, error) {
 	userPrompt := a.buildPrompt(data)
 
 	// DEBUG: Ver qué enviamos
-	log.Printf("DEBUG - Prompt sent to Cerebras:\n%s", userPrompt)
-
-	resp, err := a.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: a.model,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: systemPrompt,
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: userPrompt,
-				},
-			},
-			Temperature: 0.1,
+	log.Printf("DEBUG - Prompt sent to %s:\n%s", a.provider.Name, userPrompt)
+
+	messages := []openai.ChatCompletionMessage{
+		{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: systemPrompt,
+		},
+		{
+			Role:    openai.ChatMessageRoleUser,
+			Content: userPrompt,
 		},
-	)
+	}
+
+	attempts := a.provider.MaxRepairs + 1
+	var problems []string
+	for attempt := 1; attempt <= attempts; attempt++ {
+		responseText, err := a.complete(ctx, messages)
+		if err != nil {
+			return nil, err
+		}
+
+		// DEBUG: Ver qué responde el modelo
+		log.Printf("DEBUG - %s raw response:\n%s", a.provider.Name, responseText)
+
+		var raw *rawSignals
+		raw, problems = decodeSignals(responseText)
+		if len(problems) == 0 {
+			return a.buildSignals(raw, data), nil
+		}
+
+		log.Printf("%s response failed schema validation (attempt %d/%d): %s",
+			a.provider.Name, attempt, attempts, strings.Join(problems, "; "))
+
+		// Reparación: el modelo ve su respuesta y qué estaba mal
+		messages = append(messages,
+			openai.ChatCompletionMessage{
+				Role:    openai.ChatMessageRoleAssistant,
+				Content: responseText,
+			},
+			openai.ChatCompletionMessage{
+				Role:    openai.ChatMessageRoleUser,
+				Content: repairPrompt(problems),
+			},
+		)
+	}
 
+	return nil, &SchemaError{Provider: a.provider.Name, Attempts: attempts, Problems: problems}
+}
+
+// complete hace una llamada al proveedor y devuelve el texto de la respuesta.
+// Cualquier falla se devuelve como *TransportError.
+func (a *AIAnalyzer) complete(ctx context.Context, messages []openai.ChatCompletionMessage) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.provider.Timeout)
+	defer cancel()
+
+	req := openai.ChatCompletionRequest{
+		Model:       a.provider.Model,
+		Messages:    messages,
+		Temperature: a.provider.Temperature,
+		MaxTokens:   a.provider.MaxTokens,
+	}
+
+	// Usar el modo de salida estructurada del proveedor si lo tiene
+	switch a.provider.OutputMode {
+	case OutputJSONSchema:
+		req.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   "profile_signals",
+				Schema: signalsSchema,
+				Strict: true,
+			},
+		}
+	case OutputJSONObject:
+		req.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		}
+	}
+
+	resp, err := a.client.CreateChatCompletion(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("cerebras API error: %w", err)
+		return "", &TransportError{Provider: a.provider.Name, Err: err}
 	}
 
-	responseText := resp.Choices[0].Message.Content
-	if responseText == "" {
-		return nil, fmt.Errorf("empty response from cerebras")
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+		return "", &TransportError{Provider: a.provider.Name, Err: errors.New("empty response")}
 	}
 
-	// DEBUG: Ver qué responde Cerebras
-	log.Printf("DEBUG - Cerebras raw response:\n%s", responseText)
+	return resp.Choices[0].Message.Content, nil
+}
 
-	signals, err := a.parseSignals(responseText)
-		if err != nil {
-			return nil, err
+// repairPrompt pide al modelo que corrija una respuesta inválida.
+func repairPrompt(problems []string) string {
+	return "Tu respuesta no cumple el esquema requerido. Errores:\n- " +
+		strings.Join(problems, "\n- ") +
+		"\n\nDevuelve SOLO el JSON corregido, con exactamente los campos y valores permitidos."
+}
+
+// buildSignals convierte una respuesta ya validada en Signals y completa
+// las tecnologías de la bio que el modelo haya ignorado.
+func (a *AIAnalyzer) buildSignals(raw *rawSignals, data *profile.RawData) *profile.Signals {
+	signals := &profile.Signals{
+		Topics:          raw.Topics,
+		Sentiment:       profile.Sentiment(raw.Sentiment),
+		ExperienceLevel: profile.ExperienceLevel(raw.ExperienceLevel),
+		Keywords:        normalizeTerms(raw.Keywords),
+		TechStack:       normalizeTerms(raw.TechStack),
+		AnalyzedBy:      NameAI,
+		Setup:           data.Setup,
 	}
 
-	// Post-procesamiento: extraer hashtags de la bio si Cerebras los ignoró
+	// Post-procesamiento: extraer hashtags de la bio si el modelo los ignoró
 	if data.Bio != "" {
 		bioTechs := extractHashtagTechs(data.Bio)
 		// Agregar a TechStack si no están ya
@@ -91,65 +165,116 @@ func (a *AIAnalyzer) Analyze(data *profile.RawData) (*profile.Signals, error) {
 			}
 		}
 	}
+	signals.Evidence = aiEvidence(signals, data)
+
 	// DEBUG: Ver señales parseadas
 	log.Printf("DEBUG - Parsed signals: TechStack=%v, Topics=%v, ExpLevel=%s",
 		signals.TechStack, signals.Topics, signals.ExperienceLevel)
 
-	return signals, nil
+	return signals
 }
 
+// aiEvidence busca en los datos crudos la evidencia de cada señal que
+// devolvió el modelo. Una señal sin mención literal queda como inferida,
+// con confianza 0.5; cada mención encontrada la aumenta.
+func aiEvidence(signals *profile.Signals, data *profile.RawData) []profile.SignalEvidence {
+	terms := groupMentions(collectMentions(taxonomy.Default(), data))
+	inferred := profile.Evidence{Source: profile.SourceInferred}
+
+	var evidence []profile.SignalEvidence
+	add := func(kind profile.SignalKind, values []string) {
+		for _, v := range values {
+			ev := profile.SignalEvidence{Kind: kind, Value: v, Confidence: 0.5, Evidence: []profile.Evidence{inferred}}
+			if t, ok := terms[termKey(v)]; ok {
+				ev.Confidence = 1 - 0.5*(1-weightConfidence(t.weight))
+				ev.Evidence = t.evidence
+			}
+			evidence = append(evidence, ev)
+		}
+	}
+	add(profile.SignalTech, signals.TechStack)
+	add(profile.SignalKeyword, signals.Keywords)
+	add(profile.SignalTopic, signals.Topics)
+
+	// El nivel lo decide el modelo; las heurísticas offline lo respaldan o no
+	heuristic, _ := estimateExperience(data, len(signals.TechStack))
+	confidence := 0.5
+	if heuristic == signals.ExperienceLevel {
+		confidence = 0.8
+	}
+	evidence = append(evidence, profile.SignalEvidence{
+		Kind:       profile.SignalExperience,
+		Value:      string(signals.ExperienceLevel),
+		Confidence: confidence,
+		Evidence:   append([]profile.Evidence{inferred}, experienceFacts(data, len(signals.TechStack))...),
+	})
+
+	return evidence
+}
+
+// extractHashtagTechs devuelve las tecnologías mencionadas como hashtags en
+// la bio, en su forma canónica (#K8s → kubernetes).
 func extractHashtagTechs(bio string) []string {
+	tax := taxonomy.Default()
 	techs := []string{}
-	words := strings.Fields(bio)
-
-	techHashtags := map[string]string{
-		"#ansible":     "ansible",
-		"#k8s":         "kubernetes",
-		"#kubernetes":  "kubernetes",
-		"#docker":      "docker",
-		"#python":      "python",
-		"#go":          "go",
-		"#rust":        "rust",
-		"#javascript":  "javascript",
-		"#typescript":  "typescript",
-		"#ruby":        "ruby",
-		"#php":         "php",
-		"#java":        "java",
-		"#devops":      "devops",
-		"#terraform":   "terraform",
-		"#aws":         "aws",
-		"#gcp":         "gcp",
-		"#azure":       "azure",
-		"#linux":       "linux",
-		"#nodejs":      "nodejs",
-		"#react":       "react",
-		"#vue":         "vue",
-		"#django":      "django",
-		"#rails":       "rails",
-		"#drupal":      "drupal",
-	}
-
-	for _, word := range words {
-		wordLower := strings.ToLower(word)
-		if tech, exists := techHashtags[wordLower]; exists {
-			techs = append(techs, tech)
+
+	for _, word := range strings.Fields(bio) {
+		if !strings.HasPrefix(word, "#") {
+			continue
+		}
+		for _, id := range tax.Resolve(word) {
+			if tax.IsA(id, "tech") && !contains(techs, id) {
+				techs = append(techs, id)
+			}
 		}
 	}
 
 	return techs
 }
 
-// contains verifica si un slice contiene un string (case-insensitive)
+// normalizeTerms lleva cada valor a su forma canónica de la taxonomía
+// ("K8s" → "kubernetes") y elimina duplicados. Los valores desconocidos
+// se conservan tal cual.
+func normalizeTerms(values []string) []string {
+	tax := taxonomy.Default()
+	out := make([]string, 0, len(values))
+
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if id, ok := tax.Canonical(v); ok {
+			v = id
+		}
+		if !contains(out, v) {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// contains verifica si un slice contiene un término, comparando formas
+// canónicas ("K8s" y "kubernetes" son el mismo término).
 func contains(slice []string, item string) bool {
-	itemLower := strings.ToLower(item)
+	itemKey := termKey(item)
 	for _, s := range slice {
-		if strings.ToLower(s) == itemLower {
+		if termKey(s) == itemKey {
 			return true
 		}
 	}
 	return false
 }
 
+// termKey devuelve la clave de comparación de un término.
+func termKey(s string) string {
+	if id, ok := taxonomy.Default().Canonical(s); ok {
+		return id
+	}
+	return taxonomy.Normalize(s)
+}
+
 // buildPrompt construye el prompt de usuario con los datos crudos.
 func (a *AIAnalyzer) buildPrompt(data *profile.RawData) string {
 	var parts []string
@@ -163,6 +288,17 @@ func (a *AIAnalyzer) buildPrompt(data *profile.RawData) string {
 		repos := "Repositorios: " + strings.Join(data.Repositories, ", ")
 		parts = append(parts, repos)
 	}
+	if len(data.Topics) > 0 {
+		parts = append(parts, "Topics de repositorios: "+strings.Join(data.Topics, ", "))
+	}
+	if shares := data.LanguageShares(); len(shares) > 0 {
+		parts = append(parts, "Lenguajes por bytes de código propio: "+formatShares(shares))
+	} else if len(data.Languages) > 0 {
+		parts = append(parts, "Lenguajes de repositorios: "+strings.Join(data.Languages, ", "))
+	}
+	if data.Setup != nil {
+		parts = append(parts, "Dotfiles: "+describeSetup(data.Setup))
+	}
 	if data.Website != "" {
 		parts = append(parts, "Website: "+data.Website)
 	}
@@ -180,35 +316,38 @@ func (a *AIAnalyzer) buildPrompt(data *profile.RawData) string {
 	return strings.Join(parts, "\n\n")
 }
 
-// parseSignals limpia markdown y procesa el JSON.
-func (a *AIAnalyzer) parseSignals(content string) (*profile.Signals, error) {
-	content = strings.TrimSpace(content)
-	content = strings.TrimPrefix(content, "```json")
-	content = strings.TrimPrefix(content, "```")
-	content = strings.TrimSuffix(content, "```")
-	content = strings.TrimSpace(content)
-
-	var raw rawSignals
-	if err := json.Unmarshal([]byte(content), &raw); err != nil {
-		return nil, fmt.Errorf("failed to parse AI response: %w", err)
+// describeSetup resume lo detectado en los dotfiles
+// ("window managers: hyprland; shells: zsh; ...").
+func describeSetup(setup *profile.Setup) string {
+	var parts []string
+	for _, field := range []struct {
+		label  string
+		values []string
+	}{
+		{"window managers", setup.WindowManagers},
+		{"shells", setup.Shells},
+		{"plugins de shell", setup.ShellPlugins},
+		{"gestores de paquetes", setup.PackageManagers},
+		{"distros", setup.Distros},
+		{"herramientas", setup.Tools},
+	} {
+		if len(field.values) > 0 {
+			parts = append(parts, field.label+": "+strings.Join(field.values, ", "))
+		}
 	}
+	return strings.Join(parts, "; ")
+}
 
-	// Limpiar experience_level si viene con pipes
-	expLevel := raw.ExperienceLevel
-	if strings.Contains(expLevel, "|") {
-		// Si viene "mid|senior", tomar "senior"
-		parts := strings.Split(expLevel, "|")
-		expLevel = parts[len(parts)-1] // tomar el último (más alto)
+// formatShares muestra los lenguajes con su porcentaje ("Go 62%, Shell 20%"),
+// omitiendo los que no llegan al 1%.
+func formatShares(shares []profile.LanguageShare) string {
+	var parts []string
+	for _, s := range shares {
+		if pct := int(math.Round(s.Share * 100)); pct >= 1 {
+			parts = append(parts, fmt.Sprintf("%s %d%%", s.Language, pct))
+		}
 	}
-	expLevel = strings.TrimSpace(expLevel)
-
-	return &profile.Signals{
-		Topics:          raw.Topics,
-		Sentiment:       profile.Sentiment(raw.Sentiment),
-		ExperienceLevel: profile.ExperienceLevel(raw.ExperienceLevel),
-		Keywords:        raw.Keywords,
-		TechStack:       raw.TechStack,
-	}, nil
+	return strings.Join(parts, ", ")
 }
 
 const systemPrompt = `Eres un asistente que extrae señales estructuradas de perfiles técnicos.
@@ -216,20 +355,23 @@ const systemPrompt = `Eres un asistente que extrae señales estructuradas de per
 Debes devolver SOLO un JSON válido con este formato exacto:
 {
 "topics": ["tema1", "tema2"],
-"sentiment": "positive|neutral|negative",
-"experience_level": "junior|mid|senior",
+"sentiment": "neutral",
+"experience_level": "mid",
 "keywords": ["palabra1", "palabra2"],
 "tech_stack": ["tech1", "tech2"]
 }
 
 Reglas ESTRICTAS:
 - NO inventes información.
+- sentiment debe ser EXACTAMENTE UNO de estos valores: "positive", "neutral", "negative".
 - experience_level debe ser EXACTAMENTE UNO de estos valores: "junior", "mid", "senior". NO uses pipes ni múltiples opciones.
 - Si el perfil parece muy experto (kernel developer, creator de frameworks, mantainer de proyectos grandes), usa "senior".
 - PRIORIDAD ABSOLUTA: Si la bio contiene hashtags con tecnologías (ejemplo: #ansible, #k8s, #docker), DEBES incluirlas en tech_stack.
 - Los hashtags son indicadores directos de experiencia y deben ser incluidos siempre.
 - tech_stack debe incluir: lenguajes de programación, frameworks, herramientas DevOps, plataformas mencionadas en bio Y repositorios.
 - Extrae tecnologías tanto de la bio como de los nombres de repositorios.
+- Los lenguajes con porcentaje de bytes reflejan uso real: priorízalos en tech_stack según su porcentaje.
+- La línea Dotfiles viene de archivos de configuración reales del usuario: inclúyela en tech_stack y keywords.
 - Responde SOLO con el JSON, sin texto adicional.`
 
 type rawSignals struct {
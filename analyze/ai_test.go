@@ -0,0 +1,160 @@
+package analyze
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"distroanalyzer/profile"
+)
+
+const validReply = `{"topics":["kernel"],"sentiment":"neutral","experience_level":"senior",` +
+	`"keywords":["linux"],"tech_stack":["c","rust"]}`
+
+// fakeOpenAI es un servidor /chat/completions que responde, en orden, los
+// contenidos de replies y guarda los mensajes de cada pedido.
+type fakeOpenAI struct {
+	mu       sync.Mutex
+	replies  []string
+	status   int
+	requests [][]chatMessage
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func (f *fakeOpenAI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/chat/completions" {
+		http.NotFound(w, r)
+		return
+	}
+	var req struct {
+		Messages []chatMessage `json:"messages"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req.Messages)
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		w.Write([]byte(`{"error":{"message":"upstream failure","type":"server_error"}}`))
+		return
+	}
+
+	content := ""
+	if len(f.replies) > 0 {
+		content, f.replies = f.replies[0], f.replies[1:]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":      "chatcmpl-test",
+		"object":  "chat.completion",
+		"choices": []map[string]interface{}{{"index": 0, "message": chatMessage{Role: "assistant", Content: content}}},
+	})
+}
+
+func newTestAI(t *testing.T, f *fakeOpenAI, maxRepairs int) *AIAnalyzer {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	a, err := NewAIAnalyzer(ProviderConfig{
+		Name:       "test",
+		BaseURL:    srv.URL,
+		Model:      "test-model",
+		Timeout:    5 * time.Second,
+		OutputMode: OutputJSONObject,
+		MaxRepairs: maxRepairs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestAIAnalyzeValidResponse(t *testing.T) {
+	f := &fakeOpenAI{replies: []string{validReply}}
+	a := newTestAI(t, f, DefaultMaxRepairs)
+
+	signals, err := a.Analyze(context.Background(), &profile.RawData{Bio: "kernel hacker"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signals.AnalyzedBy != NameAI || signals.ExperienceLevel != profile.ExperienceLevel("senior") {
+		t.Errorf("signals = %+v", signals)
+	}
+	if len(f.requests) != 1 {
+		t.Errorf("requests = %d, want 1", len(f.requests))
+	}
+}
+
+func TestAIAnalyzeRepairsInvalidResponse(t *testing.T) {
+	invalid := `{"topics":[],"sentiment":"happy","experience_level":"mid|senior","keywords":[],"tech_stack":[]}`
+	f := &fakeOpenAI{replies: []string{invalid, validReply}}
+	a := newTestAI(t, f, DefaultMaxRepairs)
+
+	if _, err := a.Analyze(context.Background(), &profile.RawData{Bio: "kernel hacker"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(f.requests))
+	}
+
+	// La reparación reenvía la respuesta inválida y los errores encontrados
+	repair := f.requests[1]
+	if len(repair) != 4 {
+		t.Fatalf("repair request has %d messages, want 4", len(repair))
+	}
+	if repair[2].Role != "assistant" || repair[2].Content != invalid {
+		t.Errorf("repair[2] = %+v, want the invalid reply", repair[2])
+	}
+	if repair[3].Role != "user" || !strings.Contains(repair[3].Content, "no cumple el esquema") {
+		t.Errorf("repair[3] = %+v, want the repair prompt", repair[3])
+	}
+}
+
+func TestAIAnalyzeSchemaErrorAfterMaxRepairs(t *testing.T) {
+	f := &fakeOpenAI{replies: []string{"not json", "still not json", "nope"}}
+	a := newTestAI(t, f, 2)
+
+	_, err := a.Analyze(context.Background(), &profile.RawData{})
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("err = %v, want *SchemaError", err)
+	}
+	if schemaErr.Attempts != 3 || len(f.requests) != 3 {
+		t.Errorf("attempts = %d, requests = %d, want 3", schemaErr.Attempts, len(f.requests))
+	}
+}
+
+func TestAIAnalyzeTransportErrors(t *testing.T) {
+	for name, f := range map[string]*fakeOpenAI{
+		"http 500":       {status: http.StatusInternalServerError},
+		"empty response": {replies: []string{""}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			a := newTestAI(t, f, DefaultMaxRepairs)
+
+			_, err := a.Analyze(context.Background(), &profile.RawData{})
+			var transportErr *TransportError
+			if !errors.As(err, &transportErr) {
+				t.Fatalf("err = %v, want *TransportError", err)
+			}
+			if len(f.requests) != 1 {
+				t.Errorf("requests = %d, want 1: transport errors are not repaired", len(f.requests))
+			}
+		})
+	}
+}
@@ -4,10 +4,35 @@
 // Solo transforma datos crudos en señales observables y comparables.
 package analyze
 
-import "distroanalyzer/profile"
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"distroanalyzer/profile"
+)
 
 // Analyzer extrae señales estructuradas de datos crudos.
 type Analyzer interface {
-	// Analyze procesa RawData y devuelve Signals estructurados.
-	Analyze(data *profile.RawData) (*profile.Signals, error)
+	// Analyze procesa RawData y devuelve Signals estructurados. Si ctx se
+	// cancela, el trabajo en curso (p. ej. la llamada al LLM) se aborta.
+	Analyze(ctx context.Context, data *profile.RawData) (*profile.Signals, error)
 }
+
+// Nombres con los que cada implementación firma Signals.AnalyzedBy.
+const (
+	NameAI    = "ai"
+	NameRules = "rules"
+)
+
+// PromptVersion identifica el prompt de sistema y el esquema de señales que
+// se envían al LLM. Es un hash: cambia sola al editar cualquiera de los dos.
+var PromptVersion = func() string {
+	schema, err := json.Marshal(signalsSchema)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(append([]byte(systemPrompt), schema...))
+	return hex.EncodeToString(sum[:])[:12]
+}()
@@ -0,0 +1,86 @@
+package analyze
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreaker corta las llamadas a un servicio que viene fallando.
+//
+// Después de threshold fallas consecutivas se abre y rechaza llamadas
+// durante cooldown. Pasado ese tiempo deja pasar una sola llamada de prueba:
+// si sale bien se cierra, si falla vuelve a abrirse.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool // hay una llamada de prueba en curso
+}
+
+// NewCircuitBreaker crea un breaker cerrado.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// Allow indica si se puede intentar una llamada ahora.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// Success registra una llamada exitosa y cierra el breaker.
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure registra una llamada fallida. Al llegar al umbral, o si falla la
+// llamada de prueba, el breaker se (re)abre.
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// Cancel libera una llamada que no terminó (p. ej. el request se canceló)
+// sin contarla como éxito ni como falla. Si era la de prueba, la próxima
+// llamada vuelve a probar.
+func (b *CircuitBreaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// Open indica si el breaker está rechazando llamadas.
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.threshold
+}
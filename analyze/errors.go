@@ -0,0 +1,34 @@
+package analyze
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TransportError indica que la llamada al proveedor falló: red, HTTP,
+// timeout o una respuesta sin contenido. Reintentar más tarde puede servir.
+type TransportError struct {
+	Provider string
+	Err      error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("%s transport error: %v", e.Provider, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// SchemaError indica que el modelo respondió, pero su salida no cumple el
+// esquema de señales ni después de pedirle que la repare.
+type SchemaError struct {
+	Provider string
+	Attempts int
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s response does not match the signals schema after %d attempts: %s",
+		e.Provider, e.Attempts, strings.Join(e.Problems, "; "))
+}
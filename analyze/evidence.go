@@ -0,0 +1,224 @@
+package analyze
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+
+	"distroanalyzer/profile"
+	"distroanalyzer/taxonomy"
+)
+
+// mention es un término de la taxonomía encontrado en los datos crudos.
+type mention struct {
+	id      string
+	source  profile.EvidenceSource
+	snippet string
+	count   int
+}
+
+// Peso de cada mención según dónde aparece. La bio y los nombres de repos
+// son declaraciones deliberadas; el README y el website mencionan de todo.
+var sourceWeights = map[profile.EvidenceSource]int{
+	profile.SourceBio:     2,
+	profile.SourceRepo:    2,
+	profile.SourceReadme:  1,
+	profile.SourceWebsite: 1,
+}
+
+const (
+	readmeMaxHits = 3   // menciones del README que cuentan por término
+	maxSnippets   = 3   // fragmentos de evidencia por señal
+	snippetMaxLen = 160 // caracteres por fragmento
+)
+
+// collectMentions busca términos de la taxonomía en la bio, los nombres,
+// topics y lenguajes de repos, los dotfiles, cada línea del README y el
+// website, guardando el fragmento donde aparece cada uno.
+func collectMentions(tax *taxonomy.Taxonomy, data *profile.RawData) []mention {
+	var mentions []mention
+	add := func(source profile.EvidenceSource, snippet string, counts map[string]int) {
+		ids := make([]string, 0, len(counts))
+		for id := range counts {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			mentions = append(mentions, mention{id: id, source: source, snippet: shorten(snippet), count: counts[id]})
+		}
+	}
+
+	add(profile.SourceBio, data.Bio, tax.ResolveText(data.Bio))
+
+	for _, repo := range data.Repositories {
+		counts := map[string]int{}
+		for _, id := range tax.Resolve(repo) {
+			counts[id]++
+		}
+		add(profile.SourceRepo, repo, counts)
+	}
+
+	for _, topic := range data.Topics {
+		counts := map[string]int{}
+		for _, id := range tax.Resolve(topic) {
+			counts[id]++
+		}
+		add(profile.SourceRepo, "topic: "+topic, counts)
+	}
+
+	// Con estadísticas, cada lenguaje pesa según su proporción del código
+	shares := data.LanguageShares()
+	for _, lang := range data.Languages {
+		weight, snippet := 1, "lenguaje: "+lang
+		for _, s := range shares {
+			if s.Language == lang {
+				weight = shareWeight(s.Share)
+				snippet = fmt.Sprintf("lenguaje: %s (%.0f%% del código)", lang, s.Share*100)
+				break
+			}
+		}
+		counts := map[string]int{}
+		for _, id := range tax.Resolve(lang) {
+			counts[id] += weight
+		}
+		add(profile.SourceRepo, snippet, counts)
+	}
+
+	// Los dotfiles son configuración real: pesan como dos menciones
+	if setup := data.Setup; setup != nil {
+		snippet := "dotfiles: " + strings.Join(setup.Repos, ", ")
+		counts := map[string]int{}
+		for _, term := range setup.Terms() {
+			for _, id := range tax.Resolve(term) {
+				counts[id] += 2
+			}
+		}
+		add(profile.SourceRepo, snippet, counts)
+	}
+
+	if data.ReadmeText != nil {
+		for _, line := range strings.Split(*data.ReadmeText, "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" {
+				add(profile.SourceReadme, line, tax.ResolveText(line))
+			}
+		}
+	}
+
+	if data.Website != "" {
+		add(profile.SourceWebsite, data.Website, tax.ResolveText(websiteWords(data.Website)))
+	}
+
+	return mentions
+}
+
+// shareWeight convierte la proporción de código de un lenguaje en menciones:
+// una por aparecer, más una por cada 25% del código.
+func shareWeight(share float64) int {
+	return 1 + int(share*4)
+}
+
+// websiteWords separa host y path de una URL en palabras
+// ("https://k8s.dev/blog" → "k8s dev blog").
+func websiteWords(website string) string {
+	u, err := url.Parse(website)
+	if err != nil || u.Host == "" {
+		return website
+	}
+	return strings.NewReplacer(".", " ", "/", " ").Replace(u.Host + u.Path)
+}
+
+// termEvidence agrupa las menciones de un término.
+type termEvidence struct {
+	weight   int
+	evidence []profile.Evidence
+}
+
+// groupMentions suma el peso de las menciones de cada término y junta sus
+// fragmentos, hasta maxSnippets por término.
+func groupMentions(mentions []mention) map[string]*termEvidence {
+	terms := map[string]*termEvidence{}
+	readmeHits := map[string]int{}
+
+	for _, m := range mentions {
+		t, ok := terms[m.id]
+		if !ok {
+			t = &termEvidence{}
+			terms[m.id] = t
+		}
+
+		count := m.count
+		if m.source == profile.SourceReadme {
+			count = min(count, readmeMaxHits-readmeHits[m.id])
+			readmeHits[m.id] += count
+		}
+		t.weight += count * sourceWeights[m.source]
+
+		t.evidence = appendEvidence(t.evidence, profile.Evidence{Source: m.source, Snippet: m.snippet})
+	}
+
+	return terms
+}
+
+// appendEvidence agrega ev si no está repetida y no se llegó a maxSnippets.
+func appendEvidence(list []profile.Evidence, ev ...profile.Evidence) []profile.Evidence {
+	for _, e := range ev {
+		if len(list) >= maxSnippets {
+			break
+		}
+		duplicate := false
+		for _, existing := range list {
+			if existing == e {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// weightConfidence convierte el peso acumulado de las menciones en una
+// confianza [0, 1): cada dos puntos de peso reducen la duda a la mitad.
+func weightConfidence(weight int) float64 {
+	return 1 - math.Pow(0.5, float64(weight)/2)
+}
+
+// shorten recorta un fragmento a snippetMaxLen caracteres.
+func shorten(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= snippetMaxLen {
+		return s
+	}
+	return string(runes[:snippetMaxLen-1]) + "…"
+}
+
+// experienceFacts describe los datos usados para estimar el nivel de
+// experiencia, como evidencia legible.
+func experienceFacts(data *profile.RawData, techCount int) []profile.Evidence {
+	var facts []profile.Evidence
+
+	if n := len(data.Repositories); n > 0 {
+		facts = append(facts, profile.Evidence{Source: profile.SourceRepo, Snippet: fmt.Sprintf("%d repositorios públicos", n)})
+	}
+	if data.ReadmeText != nil && *data.ReadmeText != "" {
+		depth := measureReadme(*data.ReadmeText)
+		facts = append(facts, profile.Evidence{
+			Source:  profile.SourceReadme,
+			Snippet: fmt.Sprintf("README de %d caracteres, %d secciones y %d bloques de código", depth.length, depth.headings, depth.codeBlocks),
+		})
+	}
+	if techCount > 0 {
+		facts = append(facts, profile.Evidence{Source: profile.SourceSignals, Snippet: fmt.Sprintf("%d tecnologías detectadas", techCount)})
+	}
+	if words := bioWords(data.Bio); containsAnyWord(words, seniorWords) || containsAnyWord(words, juniorWords) {
+		facts = append(facts, profile.Evidence{Source: profile.SourceBio, Snippet: shorten(data.Bio)})
+	}
+
+	return facts
+}
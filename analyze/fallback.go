@@ -0,0 +1,85 @@
+package analyze
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"distroanalyzer/profile"
+)
+
+// FallbackAnalyzer prueba varios analizadores en orden y devuelve las señales
+// del primero que responde. Cada analizador, salvo el último, tiene su propio
+// circuit breaker para no esperar timeouts de un servicio caído.
+//
+// Si las señales no vienen del primero se marcan como Degraded.
+type FallbackAnalyzer struct {
+	stages []fallbackStage
+}
+
+type fallbackStage struct {
+	analyzer Analyzer
+	breaker  *CircuitBreaker
+}
+
+// NewFallbackAnalyzer crea la cadena. threshold y cooldown configuran los
+// breakers: fallas consecutivas para abrir y tiempo antes de reintentar.
+func NewFallbackAnalyzer(threshold int, cooldown time.Duration, analyzers ...Analyzer) (*FallbackAnalyzer, error) {
+	if len(analyzers) == 0 {
+		return nil, errors.New("fallback analyzer needs at least one analyzer")
+	}
+
+	stages := make([]fallbackStage, len(analyzers))
+	for i, a := range analyzers {
+		stages[i] = fallbackStage{analyzer: a}
+		// el último se intenta siempre: es el respaldo final
+		if i < len(analyzers)-1 {
+			stages[i].breaker = NewCircuitBreaker(threshold, cooldown)
+		}
+	}
+
+	return &FallbackAnalyzer{stages: stages}, nil
+}
+
+// Analyze devuelve las señales del primer analizador disponible que no falla.
+func (f *FallbackAnalyzer) Analyze(ctx context.Context, data *profile.RawData) (*profile.Signals, error) {
+	var errs []error
+
+	for i, stage := range f.stages {
+		if stage.breaker != nil && !stage.breaker.Allow() {
+			errs = append(errs, fmt.Errorf("analyzer %d (%T): circuit open", i, stage.analyzer))
+			continue
+		}
+
+		signals, err := stage.analyzer.Analyze(ctx, data)
+		// Una cancelación no es culpa del analizador: ni cuenta como falla
+		// ni tiene sentido probar el siguiente
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if stage.breaker != nil {
+				stage.breaker.Cancel()
+			}
+			return nil, ctxErr
+		}
+		if err != nil {
+			if stage.breaker != nil {
+				stage.breaker.Failure()
+				if stage.breaker.Open() {
+					log.Printf("analyzer %T failing, circuit open", stage.analyzer)
+				}
+			}
+			log.Printf("analyzer %T failed, trying next: %v", stage.analyzer, err)
+			errs = append(errs, fmt.Errorf("analyzer %d (%T): %w", i, stage.analyzer, err))
+			continue
+		}
+
+		if stage.breaker != nil {
+			stage.breaker.Success()
+		}
+		signals.Degraded = i > 0
+		return signals, nil
+	}
+
+	return nil, fmt.Errorf("all analyzers failed: %w", errors.Join(errs...))
+}
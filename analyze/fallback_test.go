@@ -0,0 +1,130 @@
+package analyze
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"distroanalyzer/profile"
+)
+
+// stubAnalyzer devuelve err, o espera a que se cancele ctx si block es true.
+type stubAnalyzer struct {
+	err   error
+	block bool
+	calls int
+}
+
+func (s *stubAnalyzer) Analyze(ctx context.Context, data *profile.RawData) (*profile.Signals, error) {
+	s.calls++
+	if s.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &profile.Signals{}, nil
+}
+
+func TestFallbackUsesNextAnalyzerAndMarksDegraded(t *testing.T) {
+	primary := &stubAnalyzer{err: errors.New("boom")}
+	backup := &stubAnalyzer{}
+	f, err := NewFallbackAnalyzer(1, time.Hour, primary, backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signals, err := f.Analyze(context.Background(), &profile.RawData{})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if !signals.Degraded {
+		t.Error("signals from the backup analyzer should be marked degraded")
+	}
+
+	// Con threshold 1 el breaker quedó abierto: el primario no se llama
+	if _, err := f.Analyze(context.Background(), &profile.RawData{}); err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if primary.calls != 1 {
+		t.Errorf("primary calls = %d, want 1 while the circuit is open", primary.calls)
+	}
+}
+
+func TestFallbackCancelDoesNotCountAsFailure(t *testing.T) {
+	primary := &stubAnalyzer{block: true}
+	backup := &stubAnalyzer{}
+	f, err := NewFallbackAnalyzer(1, time.Hour, primary, backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.Analyze(ctx, &profile.RawData{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if backup.calls != 0 {
+		t.Errorf("backup called %d times after cancellation", backup.calls)
+	}
+	if f.stages[0].breaker.Open() {
+		t.Error("a canceled call opened the breaker")
+	}
+}
+
+func TestFallbackCancelReleasesHalfOpenProbe(t *testing.T) {
+	primary := &stubAnalyzer{err: errors.New("boom")}
+	backup := &stubAnalyzer{}
+	f, err := NewFallbackAnalyzer(1, time.Millisecond, primary, backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Abrir el breaker y esperar el cooldown
+	if _, err := f.Analyze(context.Background(), &profile.RawData{}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// La llamada de prueba se cancela a mitad de camino
+	primary.err, primary.block = nil, true
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.Analyze(ctx, &profile.RawData{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+
+	// La próxima vuelve a probar el primario y lo cierra
+	primary.block = false
+	signals, err := f.Analyze(context.Background(), &profile.RawData{})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if signals.Degraded {
+		t.Error("the probe was not released: primary skipped after cancellation")
+	}
+	if f.stages[0].breaker.Open() {
+		t.Error("breaker still open after a successful probe")
+	}
+}
+
+func TestCircuitBreakerCancelReleasesProbe(t *testing.T) {
+	b := NewCircuitBreaker(1, 0)
+	b.Failure()
+
+	if !b.Allow() {
+		t.Fatal("cooldown elapsed: the probe should be allowed")
+	}
+	if b.Allow() {
+		t.Fatal("only one probe at a time")
+	}
+	b.Cancel()
+	if !b.Allow() {
+		t.Error("Cancel should release the probe")
+	}
+	if !b.Open() {
+		t.Error("Cancel should not close the breaker")
+	}
+}
@@ -0,0 +1,114 @@
+package analyze
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ProviderConfig describe un servidor de LLM compatible con la API de OpenAI:
+// Cerebras, OpenAI o uno propio (llama.cpp, vLLM, Ollama).
+type ProviderConfig struct {
+	// Name identifica al proveedor en logs y errores.
+	Name string
+
+	BaseURL string
+	Model   string
+
+	// APIKeyEnv es la variable de entorno que contiene la API key.
+	// Vacío = el servidor no requiere autenticación.
+	APIKeyEnv string
+	APIKey    string
+
+	Timeout time.Duration
+
+	// Temperature 0 deja la del servidor: el SDK omite el campo en cero.
+	Temperature float32
+
+	// MaxTokens limita la respuesta. 0 = límite del servidor.
+	MaxTokens int
+
+	// OutputMode es el modo de salida estructurada que soporta el servidor:
+	// OutputJSONSchema, OutputJSONObject u OutputText (solo el prompt).
+	OutputMode string
+
+	// MaxRepairs es cuántas veces se le pide al modelo que corrija una
+	// respuesta que no cumple el esquema.
+	MaxRepairs int
+}
+
+// Modos de salida estructurada.
+const (
+	OutputJSONSchema = "json_schema"
+	OutputJSONObject = "json_object"
+	OutputText       = "text"
+)
+
+// DefaultTimeout es el timeout de una llamada al LLM si no se configura otro.
+const DefaultTimeout = 30 * time.Second
+
+// knownProviders son los valores por defecto de cada proveedor conocido.
+// Cualquier campo puede sobrescribirse desde la configuración.
+var knownProviders = map[string]ProviderConfig{
+	"cerebras": {BaseURL: "https://api.cerebras.ai/v1", Model: "llama3.1-8b", APIKeyEnv: "CEREBRAS_API_KEY", OutputMode: OutputJSONSchema},
+	"openai":   {BaseURL: "https://api.openai.com/v1", Model: "gpt-4o-mini", APIKeyEnv: "OPENAI_API_KEY", OutputMode: OutputJSONSchema},
+	"ollama":   {BaseURL: "http://localhost:11434/v1", Model: "llama3.1", OutputMode: OutputJSONObject},
+	"llamacpp": {BaseURL: "http://localhost:8080/v1", Model: "default", OutputMode: OutputJSONObject},
+	"vllm":     {BaseURL: "http://localhost:8000/v1", Model: "default", OutputMode: OutputJSONObject},
+}
+
+// DefaultMaxRepairs es cuántas reparaciones se piden si no se configura otro valor.
+const DefaultMaxRepairs = 2
+
+// DefaultProvider devuelve la configuración por defecto de un proveedor
+// conocido, con timeout y temperatura estándar.
+func DefaultProvider(name string) (ProviderConfig, bool) {
+	cfg, ok := knownProviders[name]
+	if !ok {
+		return ProviderConfig{}, false
+	}
+	cfg.Name = name
+	cfg.Timeout = DefaultTimeout
+	cfg.Temperature = 0.1
+	cfg.MaxRepairs = DefaultMaxRepairs
+	return cfg, true
+}
+
+// Validate verifica que la configuración sea utilizable.
+func (c ProviderConfig) Validate() error {
+	var errs []error
+
+	if u, err := url.Parse(c.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("invalid base URL %q", c.BaseURL))
+	}
+	if c.Model == "" {
+		errs = append(errs, errors.New("model is required"))
+	}
+	if c.APIKeyEnv != "" && c.APIKey == "" {
+		errs = append(errs, fmt.Errorf("%s is required", c.APIKeyEnv))
+	}
+	if c.Timeout <= 0 {
+		errs = append(errs, errors.New("timeout must be > 0"))
+	}
+	if c.Temperature < 0 || c.Temperature > 2 {
+		errs = append(errs, fmt.Errorf("temperature must be in 0-2, got %g", c.Temperature))
+	}
+	if c.MaxTokens < 0 {
+		errs = append(errs, errors.New("max tokens must be >= 0"))
+	}
+	switch c.OutputMode {
+	case OutputJSONSchema, OutputJSONObject, OutputText:
+	default:
+		errs = append(errs, fmt.Errorf("unknown output mode %q (expected %s, %s or %s)",
+			c.OutputMode, OutputJSONSchema, OutputJSONObject, OutputText))
+	}
+	if c.MaxRepairs < 0 {
+		errs = append(errs, errors.New("max repairs must be >= 0"))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("provider %s: %w", c.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package analyze
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"distroanalyzer/profile"
+	"distroanalyzer/taxonomy"
+)
+
+// RuleAnalyzer extrae señales con diccionarios y heurísticas, sin LLM.
+//
+// Es determinístico y no necesita red: sirve como modo offline y como
+// respaldo cuando la API del modelo no está disponible.
+type RuleAnalyzer struct {
+	tax *taxonomy.Taxonomy
+}
+
+// NewRuleAnalyzer crea un analizador basado en reglas sobre la taxonomía.
+func NewRuleAnalyzer() *RuleAnalyzer {
+	return &RuleAnalyzer{tax: taxonomy.Default()}
+}
+
+const (
+	minTermWeight = 2 // peso mínimo para que un término sea señal
+	maxTerms      = 15
+)
+
+// Palabras de la bio que indican seniority, en inglés y español.
+var (
+	seniorWords = []string{"senior", "sr", "lead", "staff", "principal", "architect", "arquitecto", "maintainer", "mantenedor", "cto", "founder", "fundador"}
+	juniorWords = []string{"junior", "jr", "student", "estudiante", "learning", "aprendiendo", "bootcamp", "beginner", "principiante", "intern", "trainee"}
+
+	positiveWords = []string{"love", "passionate", "enjoy", "happy", "excited", "apasionado", "apasionada", "encanta", "disfruto"}
+	negativeWords = []string{"hate", "tired", "frustrated", "odio", "cansado", "cansada", "harto", "harta"}
+)
+
+// Analyze deriva señales de la bio, los nombres de repos, los hashtags y el
+// README. No devuelve error: un perfil vacío produce señales neutras.
+func (a *RuleAnalyzer) Analyze(_ context.Context, data *profile.RawData) (*profile.Signals, error) {
+	terms := groupMentions(collectMentions(a.tax, data))
+	weights := make(map[string]int, len(terms))
+	for id, t := range terms {
+		weights[id] = t.weight
+	}
+
+	var tech, keywords []string
+	var evidence []profile.SignalEvidence
+	termEvidence := func(kind profile.SignalKind, id string) profile.SignalEvidence {
+		return profile.SignalEvidence{
+			Kind:       kind,
+			Value:      id,
+			Confidence: weightConfidence(terms[id].weight),
+			Evidence:   terms[id].evidence,
+		}
+	}
+
+	categoryTerms := map[string][]string{}
+	for _, id := range rankTerms(weights) {
+		if weights[id] < minTermWeight {
+			continue
+		}
+		for _, root := range a.tax.Roots(id) {
+			if root == "tech" {
+				if id != root && len(tech) < maxTerms {
+					tech = append(tech, id)
+					evidence = append(evidence, termEvidence(profile.SignalTech, id))
+				}
+				continue
+			}
+			if !contains(keywords, id) && len(keywords) < maxTerms {
+				keywords = append(keywords, id)
+				evidence = append(evidence, termEvidence(profile.SignalKeyword, id))
+			}
+			if root != id {
+				categoryTerms[root] = append(categoryTerms[root], id)
+			}
+		}
+	}
+
+	// Un tema es una categoría respaldada por al menos dos términos distintos
+	topicWeights := map[string]int{}
+	for root, ids := range categoryTerms {
+		for _, id := range ids {
+			topicWeights[root] += weights[id]
+		}
+	}
+	var topics []string
+	for _, root := range rankTerms(topicWeights) {
+		if len(categoryTerms[root]) < 2 {
+			continue
+		}
+		topics = append(topics, root)
+		topic := profile.SignalEvidence{
+			Kind:       profile.SignalTopic,
+			Value:      root,
+			Confidence: weightConfidence(topicWeights[root]),
+		}
+		for _, id := range categoryTerms[root] {
+			topic.Evidence = appendEvidence(topic.Evidence, terms[id].evidence...)
+		}
+		evidence = append(evidence, topic)
+	}
+
+	level, confidence := estimateExperience(data, len(tech))
+	evidence = append(evidence, profile.SignalEvidence{
+		Kind:       profile.SignalExperience,
+		Value:      string(level),
+		Confidence: confidence,
+		Evidence:   experienceFacts(data, len(tech)),
+	})
+
+	return &profile.Signals{
+		Topics:          topics,
+		Sentiment:       detectSentiment(data.Bio),
+		ExperienceLevel: level,
+		Keywords:        keywords,
+		TechStack:       tech,
+		AnalyzedBy:      NameRules,
+		Evidence:        evidence,
+		Setup:           data.Setup,
+	}, nil
+}
+
+// rankTerms devuelve las claves ordenadas por peso descendente y, ante
+// empate, alfabéticamente, para que el resultado sea reproducible.
+func rankTerms(weights map[string]int) []string {
+	ids := make([]string, 0, len(weights))
+	for id := range weights {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if weights[ids[i]] != weights[ids[j]] {
+			return weights[ids[i]] > weights[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// Umbrales de puntos de estimateExperience.
+const (
+	seniorMinPoints = 5
+	juniorMaxPoints = 1
+)
+
+// estimateExperience suma puntos por volumen de repos, profundidad del
+// README, amplitud del stack y palabras de seniority en la bio. La confianza
+// crece con la distancia al umbral más cercano.
+func estimateExperience(data *profile.RawData, techCount int) (profile.ExperienceLevel, float64) {
+	points := 0
+
+	if len(data.Repositories) >= 5 {
+		points++
+	}
+	if len(data.Repositories) >= 10 {
+		points++
+	}
+
+	if data.ReadmeText != nil {
+		depth := measureReadme(*data.ReadmeText)
+		if depth.length >= 1500 {
+			points++
+		}
+		if depth.headings >= 4 || depth.codeBlocks >= 2 {
+			points++
+		}
+	}
+
+	if techCount >= 5 {
+		points++
+	}
+	if techCount >= 10 {
+		points++
+	}
+
+	bio := bioWords(data.Bio)
+	if containsAnyWord(bio, seniorWords) {
+		points += 2
+	}
+	if containsAnyWord(bio, juniorWords) {
+		points -= 2
+	}
+
+	switch {
+	case points >= seniorMinPoints:
+		return profile.ExpSenior, levelConfidence(points - seniorMinPoints + 1)
+	case points <= juniorMaxPoints:
+		return profile.ExpJunior, levelConfidence(juniorMaxPoints - points + 1)
+	default:
+		margin := min(points-juniorMaxPoints, seniorMinPoints-points)
+		return profile.ExpMid, levelConfidence(margin)
+	}
+}
+
+// levelConfidence: 0.5 en el borde de un umbral, +0.1 por punto de margen.
+func levelConfidence(margin int) float64 {
+	return math.Min(0.9, 0.4+0.1*float64(margin))
+}
+
+// readmeDepth resume cuán elaborado es un README.
+type readmeDepth struct {
+	length     int
+	headings   int
+	codeBlocks int
+}
+
+// measureReadme cuenta caracteres, títulos y bloques de código del README.
+func measureReadme(readme string) readmeDepth {
+	depth := readmeDepth{length: len(readme)}
+	fences := 0
+	for _, line := range strings.Split(readme, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			depth.headings++
+		}
+		if strings.HasPrefix(line, "```") {
+			fences++
+		}
+	}
+	// cada bloque de código abre y cierra con ```
+	depth.codeBlocks = fences / 2
+	return depth
+}
+
+// detectSentiment compara palabras positivas y negativas de la bio.
+func detectSentiment(bio string) profile.Sentiment {
+	words := bioWords(bio)
+	score := 0
+	for _, w := range words {
+		if slices.Contains(positiveWords, w) {
+			score++
+		}
+		if slices.Contains(negativeWords, w) {
+			score--
+		}
+	}
+
+	switch {
+	case score > 0:
+		return profile.SentimentPos
+	case score < 0:
+		return profile.SentimentNeg
+	default:
+		return profile.SentimentNeu
+	}
+}
+
+// bioWords separa la bio en palabras normalizadas.
+func bioWords(bio string) []string {
+	fields := strings.Fields(bio)
+	words := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if w := taxonomy.Normalize(strings.Trim(f, "()[]{}\"'`*|")); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// containsAnyWord indica si alguna palabra está en el diccionario.
+func containsAnyWord(words, dictionary []string) bool {
+	for _, w := range words {
+		if slices.Contains(dictionary, w) {
+			return true
+		}
+	}
+	return false
+}
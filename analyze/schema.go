@@ -0,0 +1,194 @@
+package analyze
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// jsonSchema es el subconjunto de JSON Schema que usamos: alcanza para
+// describir la respuesta del LLM y es el que aceptan los modos de salida
+// estructurada de los proveedores compatibles con OpenAI.
+type jsonSchema struct {
+	Type                 string                 `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+}
+
+// MarshalJSON permite pasar el esquema como json.Marshaler al SDK.
+func (s *jsonSchema) MarshalJSON() ([]byte, error) {
+	type plain jsonSchema
+	return json.Marshal((*plain)(s))
+}
+
+// signalsSchema describe la respuesta que pedimos al LLM. Es la única fuente
+// de verdad: se envía al proveedor y se usa para validar lo que devuelve.
+var signalsSchema = func() *jsonSchema {
+	no := false
+	stringList := &jsonSchema{Type: "array", Items: &jsonSchema{Type: "string"}}
+	return &jsonSchema{
+		Type: "object",
+		Properties: map[string]*jsonSchema{
+			"topics":           stringList,
+			"sentiment":        {Type: "string", Enum: []string{"positive", "neutral", "negative"}},
+			"experience_level": {Type: "string", Enum: []string{"junior", "mid", "senior"}},
+			"keywords":         stringList,
+			"tech_stack":       stringList,
+		},
+		Required:             []string{"topics", "sentiment", "experience_level", "keywords", "tech_stack"},
+		AdditionalProperties: &no,
+	}
+}()
+
+// validate devuelve los problemas de value respecto del esquema, con la ruta
+// de cada uno ("$.sentiment: ..."). Vacío si value es válido.
+func (s *jsonSchema) validate(value any, path string) []string {
+	var problems []string
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected object, got %s", path, jsonType(value))}
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s.%s: required field missing", path, name))
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					problems = append(problems, fmt.Sprintf("%s.%s: unexpected field", path, name))
+				}
+				continue
+			}
+			problems = append(problems, prop.validate(obj[name], path+"."+name)...)
+		}
+
+	case "array":
+		list, ok := value.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected array, got %s", path, jsonType(value))}
+		}
+		for i, item := range list {
+			problems = append(problems, s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected string, got %s", path, jsonType(value))}
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			problems = append(problems, fmt.Sprintf("%s: %q is not one of %s", path, str, strings.Join(s.Enum, ", ")))
+		}
+	}
+
+	return problems
+}
+
+// jsonType nombra el tipo JSON de un valor decodificado, para los mensajes.
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// decodeSignals extrae el JSON de la respuesta del modelo, corrige las
+// desviaciones inofensivas de los enums y lo valida contra signalsSchema.
+// Los problemas que no se pueden corregir se devuelven para pedir una
+// reparación al modelo.
+func decodeSignals(content string) (*rawSignals, []string) {
+	content = extractJSON(content)
+	if content == "" {
+		return nil, []string{"$: response does not contain a JSON object"}
+	}
+
+	dec := json.NewDecoder(strings.NewReader(content))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, []string{fmt.Sprintf("$: invalid JSON: %v", err)}
+	}
+
+	if obj, ok := value.(map[string]any); ok {
+		if v, ok := obj["sentiment"]; ok {
+			obj["sentiment"] = normalizeEnum(v)
+		}
+		if v, ok := obj["experience_level"]; ok {
+			obj["experience_level"] = normalizeLevel(v)
+		}
+	}
+
+	if problems := signalsSchema.validate(value, "$"); len(problems) > 0 {
+		return nil, problems
+	}
+
+	// Ya validado: el re-encode solo pasa el mapa al struct tipado
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("$: %v", err)}
+	}
+	var raw rawSignals
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, []string{fmt.Sprintf("$: %v", err)}
+	}
+	return &raw, nil
+}
+
+// extractJSON quita fences de markdown y texto alrededor del objeto JSON.
+func extractJSON(content string) string {
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return ""
+	}
+	return content[start : end+1]
+}
+
+// normalizeEnum limpia un valor de enum: minúsculas y sin espacios.
+func normalizeEnum(value any) any {
+	if str, ok := value.(string); ok {
+		return strings.ToLower(strings.TrimSpace(str))
+	}
+	return value
+}
+
+// normalizeLevel limpia experience_level. Si el modelo copió las opciones
+// del prompt ("mid|senior") toma la última, que es la más alta.
+func normalizeLevel(value any) any {
+	if str, ok := value.(string); ok && strings.Contains(str, "|") {
+		parts := strings.Split(str, "|")
+		value = parts[len(parts)-1]
+	}
+	return normalizeEnum(value)
+}
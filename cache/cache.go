@@ -0,0 +1,20 @@
+// Package cache guarda perfiles ya analizados para no repetir el pipeline
+// (collectors, LLM y scoring) con cada request.
+package cache
+
+import (
+	"context"
+	"time"
+
+	"distroanalyzer/profile"
+)
+
+// Cache guarda perfiles por clave con un tiempo de vida.
+type Cache interface {
+	// Get devuelve el perfil guardado en key, o nil si no está o venció.
+	// Un error indica que no se pudo consultar, no que falte la clave.
+	Get(ctx context.Context, key string) (*profile.Profile, error)
+
+	// Set guarda p en key por ttl. Con ttl <= 0 no vence.
+	Set(ctx context.Context, key string, p *profile.Profile, ttl time.Duration) error
+}
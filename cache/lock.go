@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Locker es un cache compartido que además coordina trabajo entre
+// instancias: mientras una tiene la clave, las demás esperan.
+type Locker interface {
+	// Lock espera hasta tomar key o hasta que ctx se cancele. El lock vence
+	// solo después de ttl, por si quien lo tiene se cae. unlock lo suelta
+	// si todavía es nuestro.
+	Lock(ctx context.Context, key string, ttl time.Duration) (unlock func(), err error)
+}
+
+// lockPollInterval es cada cuánto se reintenta tomar un lock ocupado.
+const lockPollInterval = 100 * time.Millisecond
+
+// unlockScript borra el lock solo si sigue teniendo nuestro token: si venció
+// y lo tomó otra instancia, no se lo sacamos.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Lock toma key con SET NX y un token propio, reintentando mientras otra
+// instancia lo tenga.
+func (c *RedisCache) Lock(ctx context.Context, key string, ttl time.Duration) (func(), error) {
+	lockKey := redisKeyPrefix + "lock:" + key
+	token, err := lockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+	for {
+		ok, err := c.client.SetNX(ctx, lockKey, token, ttl).Result()
+		if err != nil {
+			return nil, fmt.Errorf("redis lock %s: %w", key, err)
+		}
+		if ok {
+			break
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	unlock := func() {
+		// El request puede estar cancelado: soltar el lock igual
+		ctx, cancel := context.WithTimeout(context.Background(), redisPingTimeout)
+		defer cancel()
+		unlockScript.Run(ctx, c.client, []string{lockKey}, token)
+	}
+	return unlock, nil
+}
+
+// lockToken identifica a quien tiene un lock.
+func lockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
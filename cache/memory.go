@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"distroanalyzer/profile"
+)
+
+// MemoryOptions ajusta MemoryCache. Los campos en cero usan los valores
+// por defecto.
+type MemoryOptions struct {
+	// MaxEntries es cuántos perfiles se guardan como máximo; al pasarse se
+	// descarta el usado hace más tiempo.
+	MaxEntries int
+
+	// SweepInterval es cada cuánto se borran los perfiles vencidos, aunque
+	// nadie los pida.
+	SweepInterval time.Duration
+}
+
+// Valores por defecto de MemoryOptions.
+const (
+	DefaultMemoryMaxEntries    = 1000
+	DefaultMemorySweepInterval = time.Minute
+)
+
+// MemoryCache es un cache en memoria con TTL y desalojo LRU, para una
+// sola instancia. Los perfiles se guardan tal cual, sin copiarlos.
+//
+// Una goroutine barre los vencidos periódicamente; Close la detiene.
+type MemoryCache struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List // frente = usado más recientemente
+	maxEntries int
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+type memoryEntry struct {
+	key     string
+	profile *profile.Profile
+	expires time.Time // cero = no vence
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+// NewMemoryCache crea el cache y arranca el barrido de vencidos.
+func NewMemoryCache(opts MemoryOptions) *MemoryCache {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultMemoryMaxEntries
+	}
+	if opts.SweepInterval <= 0 {
+		opts.SweepInterval = DefaultMemorySweepInterval
+	}
+
+	c := &MemoryCache{
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		maxEntries: opts.MaxEntries,
+		stop:       make(chan struct{}),
+	}
+	go c.sweepLoop(opts.SweepInterval)
+	return c
+}
+
+// Get devuelve el perfil de key y lo marca como usado.
+func (c *MemoryCache) Get(ctx context.Context, key string) (*profile.Profile, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	if entry.expired(time.Now()) {
+		c.remove(elem)
+		return nil, nil
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.profile, nil
+}
+
+// Set guarda p en key y, si el cache está lleno, descarta el menos usado.
+func (c *MemoryCache) Set(ctx context.Context, key string, p *profile.Profile, ttl time.Duration) error {
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.profile, entry.expires = p, expires
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, profile: p, expires: expires})
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// Len devuelve cuántos perfiles hay guardados, incluidos los vencidos que
+// todavía no se barrieron.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Close detiene el barrido. El cache sigue funcionando, pero los vencidos
+// solo se borran al pedirlos.
+func (c *MemoryCache) Close() error {
+	c.closeOnce.Do(func() { close(c.stop) })
+	return nil
+}
+
+func (c *MemoryCache) sweepLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.sweep(time.Now())
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// sweep borra todos los perfiles vencidos a now.
+func (c *MemoryCache) sweep(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.order.Back(); elem != nil; {
+		prev := elem.Prev()
+		if elem.Value.(*memoryEntry).expired(now) {
+			c.remove(elem)
+		}
+		elem = prev
+	}
+}
+
+// remove saca elem del cache. Requiere tener c.mu.
+func (c *MemoryCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*memoryEntry).key)
+}
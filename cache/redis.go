@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"distroanalyzer/profile"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix separa nuestras claves de las de otras apps que compartan
+// la base de Redis.
+const redisKeyPrefix = "distroanalyzer:"
+
+// redisPingTimeout es cuánto se espera a Redis al conectar.
+const redisPingTimeout = 5 * time.Second
+
+// RedisCache guarda los perfiles como JSON en Redis, con el TTL nativo de
+// cada clave. Sirve para compartir el cache entre varias instancias.
+type RedisCache struct {
+	client redis.UniversalClient
+}
+
+// NewRedisCache conecta con Redis en addr y verifica la conexión.
+func NewRedisCache(addr, password string, db int) (*RedisCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisPingTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("connect to redis at %s: %w", addr, err)
+	}
+
+	return NewRedisCacheWithClient(client), nil
+}
+
+// NewRedisCacheWithClient usa un cliente ya configurado (cluster, sentinel
+// o un Redis en proceso para pruebas).
+func NewRedisCacheWithClient(client redis.UniversalClient) *RedisCache {
+	return &RedisCache{client: client}
+}
+
+// Get devuelve el perfil de key. Una clave que falta o venció no es error.
+func (c *RedisCache) Get(ctx context.Context, key string) (*profile.Profile, error) {
+	data, err := c.client.Get(ctx, redisKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get %s: %w", key, err)
+	}
+
+	var p profile.Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("decode cached profile %s: %w", key, err)
+	}
+	return &p, nil
+}
+
+// Set guarda p en key por ttl.
+func (c *RedisCache) Set(ctx context.Context, key string, p *profile.Profile, ttl time.Duration) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("encode profile %s: %w", key, err)
+	}
+
+	// En go-redis, expiración 0 = sin TTL
+	if err := c.client.Set(ctx, redisKeyPrefix+key, data, max(ttl, 0)).Err(); err != nil {
+		return fmt.Errorf("redis set %s: %w", key, err)
+	}
+	return nil
+}
+
+// Close cierra la conexión con Redis.
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
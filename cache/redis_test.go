@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"distroanalyzer/profile"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	c := NewRedisCacheWithClient(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	t.Cleanup(func() { c.Close() })
+	return c, mr
+}
+
+func TestRedisGetMiss(t *testing.T) {
+	c, _ := newTestRedis(t)
+
+	p, err := c.Get(context.Background(), "github:nobody")
+	if err != nil || p != nil {
+		t.Errorf("Get = %v, %v; want nil, nil", p, err)
+	}
+}
+
+func TestRedisSetGet(t *testing.T) {
+	c, mr := newTestRedis(t)
+	ctx := context.Background()
+
+	want := &profile.Profile{Username: "alice", Source: "github"}
+	if err := c.Set(ctx, "github:alice", want, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if !mr.Exists(redisKeyPrefix + "github:alice") {
+		t.Errorf("key is not stored under the %q prefix", redisKeyPrefix)
+	}
+
+	got, err := c.Get(ctx, "github:alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Username != want.Username || got.Source != want.Source {
+		t.Errorf("Get = %+v, want %+v", got, want)
+	}
+}
+
+func TestRedisTTL(t *testing.T) {
+	c, mr := newTestRedis(t)
+	ctx := context.Background()
+
+	p := &profile.Profile{Username: "alice"}
+	if err := c.Set(ctx, "expiring", p, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set(ctx, "forever", p, 0); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := mr.TTL(redisKeyPrefix + "expiring"); ttl != time.Minute {
+		t.Errorf("TTL = %v, want 1m", ttl)
+	}
+
+	mr.FastForward(2 * time.Minute)
+	if got, _ := c.Get(ctx, "expiring"); got != nil {
+		t.Errorf("expired key still returned %+v", got)
+	}
+	if got, _ := c.Get(ctx, "forever"); got == nil {
+		t.Error("key without TTL expired")
+	}
+}
+
+func TestRedisGetUnavailable(t *testing.T) {
+	c, mr := newTestRedis(t)
+	mr.Close()
+
+	if _, err := c.Get(context.Background(), "github:alice"); err == nil {
+		t.Error("Get with Redis down returned no error")
+	}
+}
+
+func TestRedisLockExclusive(t *testing.T) {
+	c, _ := newTestRedis(t)
+	ctx := context.Background()
+
+	unlock, err := c.Lock(ctx, "github:alice", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Mientras está tomado, otro Lock espera hasta que se cancele ctx
+	waitCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	if _, err := c.Lock(waitCtx, "github:alice", time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("second Lock = %v, want DeadlineExceeded", err)
+	}
+
+	// Al soltarlo, el que espera lo toma
+	acquired := make(chan error, 1)
+	go func() {
+		unlock2, err := c.Lock(ctx, "github:alice", time.Minute)
+		if err == nil {
+			unlock2()
+		}
+		acquired <- err
+	}()
+	unlock()
+
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Lock was not acquired after unlock")
+	}
+}
+
+func TestRedisLockExpires(t *testing.T) {
+	c, mr := newTestRedis(t)
+	ctx := context.Background()
+
+	if _, err := c.Lock(ctx, "github:alice", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// Quien lo tenía se cayó sin soltarlo: vence solo
+	mr.FastForward(2 * time.Second)
+	waitCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if _, err := c.Lock(waitCtx, "github:alice", time.Minute); err != nil {
+		t.Fatalf("Lock after expiry = %v", err)
+	}
+}
+
+func TestRedisUnlockKeepsOtherHolder(t *testing.T) {
+	c, mr := newTestRedis(t)
+	ctx := context.Background()
+	lockKey := redisKeyPrefix + "lock:github:alice"
+
+	stale, err := c.Lock(ctx, "github:alice", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr.FastForward(2 * time.Second)
+	unlock, err := c.Lock(ctx, "github:alice", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// El primer dueño suelta tarde: no debe borrar el lock del segundo
+	stale()
+	if !mr.Exists(lockKey) {
+		t.Fatal("stale unlock deleted the new holder's lock")
+	}
+
+	unlock()
+	if mr.Exists(lockKey) {
+		t.Error("unlock did not release the lock")
+	}
+}
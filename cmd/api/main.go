@@ -2,10 +2,12 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -33,7 +35,7 @@ func main() {
 
 	// 3. Crear handler HTTP
 	handler, err := httpapi.NewHandler(
-		components.collector,
+		components.collectors,
 		components.analyzer,
 		components.engine,
 		components.explainer,
@@ -59,20 +61,23 @@ func main() {
 
 	// 6. Iniciar servidor en goroutine
 	go func() {
-		log.Printf("Server listening on %s (Using Cerebras API)", cfg.ServerAddr)
+		log.Printf("Server listening on %s", cfg.ServerAddr)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server error: %v", err)
 		}
 	}()
 
-	// 7. Esperar señal de shutdown
+	// 7. Recargar catálogo y presets de scoring con SIGHUP
+	go watchReload(cfg, components.engine)
+
+	// 8. Esperar señal de shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Println("Shutting down server...")
 
-	// 8. Graceful shutdown
+	// 9. Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -93,36 +98,77 @@ type Config struct {
 	RedisPass       string
 	RedisDB         int
 	GithubToken     string
-	CerebrasAPIKey  string // Cambiado de Gemini
-	CerebrasModel   string // Cambiado de Gemini
+	GitHub          collect.GitHubOptions
+	GitLabURL       string // Instancia de GitLab (gitlab.com o self-hosted)
+	GitLabToken     string
+	CodebergToken   string
+	GiteaURL        string // Instancia propia de Gitea/Forgejo. Vacío = solo Codeberg
+	GiteaToken      string
+	Web             collect.WebOptions
+	CatalogPath     string        // Vacío = catálogo incluido en el binario
+	PresetsPath     string        // Vacío = presets incluidos en el binario
+	Analyzer        string        // "auto", "ai" o "rules"
+	BreakerFailures int           // fallas consecutivas que abren el circuit breaker del LLM
+	BreakerCooldown time.Duration // tiempo con el circuito abierto antes de reintentar
+	LLM             analyze.ProviderConfig
 	UseRedis        bool
+	MemoryCache     cache.MemoryOptions
 }
 
 // loadConfig carga la configuración desde variables de entorno.
 func loadConfig() *Config {
 	return &Config{
-		ServerAddr:      getEnv("SERVER_ADDR", ":8080"),
-		TemplatesDir:    getEnv("TEMPLATES_DIR", "./web/templates"),
-		StaticDir:       getEnv("STATIC_DIR", "./web/static"),
-		DBPath:          getEnv("DB_PATH", "./data/distroanalyzer.db"),
-		RedisAddr:       getEnv("REDIS_ADDR", "localhost:6379"),
-		RedisPass:       getEnv("REDIS_PASSWORD", ""),
-		RedisDB:         0,
-		GithubToken:     getEnv("GITHUB_TOKEN", ""),
-		CerebrasAPIKey:  getEnv("CEREBRAS_API_KEY", ""), // Busca la nueva variable
-		CerebrasModel:   getEnv("CEREBRAS_MODEL", "llama3.1-8b"), // Modelo por defecto de Cerebras
+		ServerAddr:   getEnv("SERVER_ADDR", ":8080"),
+		TemplatesDir: getEnv("TEMPLATES_DIR", "./web/templates"),
+		StaticDir:    getEnv("STATIC_DIR", "./web/static"),
+		DBPath:       getEnv("DB_PATH", "./data/distroanalyzer.db"),
+		RedisAddr:    getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPass:    getEnv("REDIS_PASSWORD", ""),
+		RedisDB:      getEnvInt("REDIS_DB", 0),
+		GithubToken:  getEnv("GITHUB_TOKEN", ""),
+		GitHub: collect.GitHubOptions{
+			APIURL:           getEnv("GITHUB_API_URL", collect.DefaultGitHubAPI),
+			MaxRepos:         getEnvInt("GITHUB_MAX_REPOS", collect.DefaultGitHubMaxRepos),
+			MaxReadmes:       getEnvInt("GITHUB_MAX_READMES", collect.DefaultGitHubMaxReadmes),
+			ReadmeBudget:     getEnvInt("GITHUB_README_BUDGET", collect.DefaultGitHubReadmeBudget),
+			RateLimitMaxWait: getEnvDuration("GITHUB_RATE_LIMIT_MAX_WAIT", collect.DefaultGitHubRateLimitMaxWait),
+			ETagCacheSize:    getEnvInt("GITHUB_ETAG_CACHE_SIZE", collect.DefaultGitHubETagCacheSize),
+			LanguageRepos:    getEnvInt("GITHUB_LANGUAGE_REPOS", 0), // 0 = 5 con token, ninguno sin
+		},
+		GitLabURL:     getEnv("GITLAB_URL", collect.DefaultGitLabURL),
+		GitLabToken:   getEnv("GITLAB_TOKEN", ""),
+		CodebergToken: getEnv("CODEBERG_TOKEN", ""),
+		GiteaURL:      getEnv("GITEA_URL", ""),
+		GiteaToken:    getEnv("GITEA_TOKEN", ""),
+		Web: collect.WebOptions{
+			UserAgent:     getEnv("WEB_USER_AGENT", collect.DefaultWebUserAgent),
+			MaxBodyBytes:  getEnvInt("WEB_MAX_BODY_BYTES", collect.DefaultWebMaxBodyBytes),
+			MaxCrawlDelay: getEnvDuration("WEB_MAX_CRAWL_DELAY", collect.DefaultWebMaxCrawlDelay),
+			RobotsTTL:     getEnvDuration("WEB_ROBOTS_TTL", collect.DefaultWebRobotsTTL),
+			AllowPrivate:  getEnv("WEB_ALLOW_PRIVATE", "false") == "true",
+		},
+		CatalogPath:     getEnv("CATALOG_PATH", ""),
+		PresetsPath:     getEnv("ENGINE_PRESETS_PATH", ""),
+		Analyzer:        getEnv("ANALYZER", "auto"),
+		BreakerFailures: getEnvInt("ANALYZER_BREAKER_FAILURES", 3),
+		BreakerCooldown: getEnvDuration("ANALYZER_BREAKER_COOLDOWN", time.Minute),
+		LLM:             loadProvider(),
 		UseRedis:        getEnv("USE_REDIS", "false") == "true",
+		MemoryCache: cache.MemoryOptions{
+			MaxEntries:    getEnvInt("CACHE_MAX_ENTRIES", cache.DefaultMemoryMaxEntries),
+			SweepInterval: getEnvDuration("CACHE_SWEEP_INTERVAL", cache.DefaultMemorySweepInterval),
+		},
 	}
 }
 
 // Components agrupa todos los componentes inicializados.
 type Components struct {
-	collector collect.Collector
-	analyzer  analyze.Analyzer
-	engine    *score.Engine
-	explainer explain.Explainer
-	cache     cache.Cache
-	store     store.Store
+	collectors *collect.Registry
+	analyzer   analyze.Analyzer
+	engine     *score.Engine
+	explainer  explain.Explainer
+	cache      cache.Cache
+	store      store.Store
 }
 
 func (c *Components) cleanup() {
@@ -140,23 +186,47 @@ func (c *Components) cleanup() {
 
 // initComponents inicializa todos los componentes del sistema.
 func initComponents(cfg *Config) (*Components, error) {
-	// 1. Collector (GitHub)
-	collector := collect.NewGitHubCollector(cfg.GithubToken)
+	// 1. Collectors (la primera fuente registrada es la default)
+	collectors := collect.NewRegistry()
+	collectors.Register("github", collect.NewGitHubCollector(cfg.GithubToken, cfg.GitHub))
+	collectors.Register("gitlab", collect.NewGitLabCollector(cfg.GitLabURL, cfg.GitLabToken))
+	collectors.Register("codeberg", collect.NewGiteaCollector(collect.DefaultCodebergURL, cfg.CodebergToken))
+	if cfg.GiteaURL != "" {
+		collectors.Register("gitea", collect.NewGiteaCollector(cfg.GiteaURL, cfg.GiteaToken))
+	}
+	collectors.Register(collect.SourceWeb, collect.NewWebCollector(cfg.Web))
 
-	// 2. Analyzer (Cerebras)
-	if cfg.CerebrasAPIKey == "" {
-		log.Println("WARNING: CEREBRAS_API_KEY not set, analysis will fail")
+	// Instancias self-hosted: "gitlab:user@host", "forgejo:user@host". Las
+	// elige el usuario, así que solo se conecta a direcciones públicas
+	collectors.RegisterInstances("gitlab", func(baseURL string) collect.Collector {
+		return collect.NewGitLabInstanceCollector(baseURL)
+	})
+	giteaInstance := func(baseURL string) collect.Collector {
+		return collect.NewGiteaInstanceCollector(baseURL)
 	}
+	collectors.RegisterInstances("gitea", giteaInstance)
+	collectors.RegisterInstances("forgejo", giteaInstance)
 
-	// Usamos el constructor que actualizamos en ai.go
-	analyzer, err := analyze.NewAIAnalyzer(cfg.CerebrasAPIKey, cfg.CerebrasModel)
+	// 2. Analyzer (LLM o reglas offline)
+	analyzer, err := newAnalyzer(cfg)
 	if err != nil {
 		return nil, err
 	}
 
 	// 3. Scoring engine
-	distros := score.Top50Distros()
-	engine := score.NewEngine(distros)
+	catalog, err := loadCatalog(cfg.CatalogPath)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Loaded distro catalog %s (%d distros)", catalog.Version, len(catalog.Distros))
+
+	presets, err := loadPresets(cfg.PresetsPath)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Loaded scoring presets: %v", presets.Names())
+
+	engine := score.NewEngine(catalog, presets)
 
 	// 4. Explainer
 	explainer := explain.NewSimpleExplainer()
@@ -168,13 +238,13 @@ func initComponents(cfg *Config) (*Components, error) {
 		redisCache, err := cache.NewRedisCache(cfg.RedisAddr, cfg.RedisPass, cfg.RedisDB)
 		if err != nil {
 			log.Printf("Redis connection failed, falling back to memory cache: %v", err)
-			cacheImpl = cache.NewMemoryCache()
+			cacheImpl = cache.NewMemoryCache(cfg.MemoryCache)
 		} else {
 			cacheImpl = redisCache
 		}
 	} else {
 		log.Println("Using in-memory cache")
-		cacheImpl = cache.NewMemoryCache()
+		cacheImpl = cache.NewMemoryCache(cfg.MemoryCache)
 	}
 
 	// 6. Store
@@ -185,18 +255,157 @@ func initComponents(cfg *Config) (*Components, error) {
 	}
 
 	return &Components{
-		collector: collector,
-		analyzer:  analyzer,
-		engine:    engine,
-		explainer: explainer,
-		cache:     cacheImpl,
-		store:     storeImpl,
+		collectors: collectors,
+		analyzer:   analyzer,
+		engine:     engine,
+		explainer:  explainer,
+		cache:      cacheImpl,
+		store:      storeImpl,
 	}, nil
 }
 
+// newAnalyzer elige el analizador según ANALYZER. En "auto" usa el LLM con
+// respaldo por reglas si hay API key, y solo reglas si no.
+func newAnalyzer(cfg *Config) (analyze.Analyzer, error) {
+	switch cfg.Analyzer {
+	case "ai":
+		log.Printf("Using %s analyzer (%s at %s)", cfg.LLM.Name, cfg.LLM.Model, cfg.LLM.BaseURL)
+		return analyze.NewAIAnalyzer(cfg.LLM)
+	case "rules":
+		log.Println("Using rule-based analyzer (offline)")
+		return analyze.NewRuleAnalyzer(), nil
+	case "auto":
+		if cfg.LLM.APIKeyEnv != "" && cfg.LLM.APIKey == "" {
+			log.Printf("%s not set, using rule-based analyzer (offline)", cfg.LLM.APIKeyEnv)
+			return analyze.NewRuleAnalyzer(), nil
+		}
+		ai, err := analyze.NewAIAnalyzer(cfg.LLM)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Using %s analyzer (%s at %s) with rule-based fallback", cfg.LLM.Name, cfg.LLM.Model, cfg.LLM.BaseURL)
+		return analyze.NewFallbackAnalyzer(cfg.BreakerFailures, cfg.BreakerCooldown, ai, analyze.NewRuleAnalyzer())
+	}
+	return nil, fmt.Errorf("unknown ANALYZER %q (expected auto, ai or rules)", cfg.Analyzer)
+}
+
+// loadProvider arma la configuración del LLM: parte de los valores por
+// defecto de LLM_PROVIDER y aplica los LLM_* definidos. Un proveedor que no
+// es conocido necesita al menos LLM_BASE_URL y LLM_MODEL.
+func loadProvider() analyze.ProviderConfig {
+	name := getEnv("LLM_PROVIDER", "cerebras")
+	provider, ok := analyze.DefaultProvider(name)
+	if !ok {
+		provider = analyze.ProviderConfig{
+			Name:        name,
+			Timeout:     analyze.DefaultTimeout,
+			Temperature: 0.1,
+			OutputMode:  analyze.OutputText,
+			MaxRepairs:  analyze.DefaultMaxRepairs,
+		}
+	}
+
+	// Compatibilidad con la configuración anterior, solo para Cerebras
+	if name == "cerebras" {
+		provider.Model = getEnv("CEREBRAS_MODEL", provider.Model)
+	}
+
+	provider.BaseURL = getEnv("LLM_BASE_URL", provider.BaseURL)
+	provider.Model = getEnv("LLM_MODEL", provider.Model)
+	provider.APIKeyEnv = getEnv("LLM_API_KEY_ENV", provider.APIKeyEnv)
+	if provider.APIKeyEnv != "" {
+		provider.APIKey = os.Getenv(provider.APIKeyEnv)
+	}
+	provider.Timeout = getEnvDuration("LLM_TIMEOUT", provider.Timeout)
+	provider.Temperature = float32(getEnvFloat("LLM_TEMPERATURE", float64(provider.Temperature)))
+	provider.MaxTokens = getEnvInt("LLM_MAX_TOKENS", provider.MaxTokens)
+	provider.OutputMode = getEnv("LLM_OUTPUT_MODE", provider.OutputMode)
+	provider.MaxRepairs = getEnvInt("LLM_MAX_REPAIRS", provider.MaxRepairs)
+
+	return provider
+}
+
+// loadCatalog carga el catálogo desde path, o el incluido si path está vacío.
+func loadCatalog(path string) (*score.Catalog, error) {
+	if path == "" {
+		return score.DefaultCatalog()
+	}
+	return score.LoadCatalog(path)
+}
+
+// loadPresets carga los presets desde path, o los incluidos si path está vacío.
+func loadPresets(path string) (*score.Presets, error) {
+	if path == "" {
+		return score.DefaultPresets()
+	}
+	return score.LoadPresets(path)
+}
+
+// watchReload recarga catálogo y presets cada vez que llega SIGHUP.
+// Si un archivo nuevo es inválido se conserva la versión anterior.
+func watchReload(cfg *Config, engine *score.Engine) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	for range hup {
+		if cfg.CatalogPath == "" && cfg.PresetsPath == "" {
+			log.Println("SIGHUP received but CATALOG_PATH and ENGINE_PRESETS_PATH are not set, nothing to reload")
+			continue
+		}
+
+		if cfg.CatalogPath != "" {
+			catalog, err := score.LoadCatalog(cfg.CatalogPath)
+			if err != nil {
+				log.Printf("catalog reload failed, keeping version %s: %v", engine.Catalog().Version, err)
+			} else {
+				engine.SetCatalog(catalog)
+				log.Printf("Reloaded distro catalog %s (%d distros)", catalog.Version, len(catalog.Distros))
+			}
+		}
+
+		if cfg.PresetsPath != "" {
+			presets, err := score.LoadPresets(cfg.PresetsPath)
+			if err != nil {
+				log.Printf("presets reload failed, keeping current presets: %v", err)
+			} else {
+				engine.SetPresets(presets)
+				log.Printf("Reloaded scoring presets: %v", presets.Names())
+			}
+		}
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+// getEnvInt lee un entero; si falta o es inválido usa defaultValue.
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvFloat lee un número decimal; si falta o es inválido usa defaultValue.
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvDuration lee una duración ("30s", "2m"); si falta o es inválida
+// usa defaultValue.
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -1,10 +1,111 @@
 // Package collect define la interfaz y tipos para recolectar datos de perfiles.
 package collect
 
-import "distroanalyzer/profile"
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"distroanalyzer/profile"
+)
 
 // Collector representa cualquier fuente capaz de recolectar datos crudos.
 type Collector interface {
 	// Collect obtiene datos a partir de un identificador (username, URL, etc).
-	Collect(input string) (*profile.RawData, error)
+	// Si ctx se cancela, los pedidos en curso se abortan y devuelve su error.
+	Collect(ctx context.Context, input string) (*profile.RawData, error)
+}
+
+// Registry asocia nombres de fuente ("github", "gitlab") con su collector.
+// También es un Collector: Collect elige la fuente según la sintaxis de la
+// entrada (ver Resolve).
+type Registry struct {
+	names      []string
+	collectors map[string]Collector
+
+	// Fuentes que aceptan instancias self-hosted ("gitlab:user@host")
+	factories map[string]InstanceFactory
+}
+
+// InstanceFactory crea un collector para una instancia self-hosted, a
+// partir de su URL base ("https://gitlab.example.com").
+type InstanceFactory func(baseURL string) Collector
+
+// NewRegistry crea un registro vacío.
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: make(map[string]Collector),
+		factories:  make(map[string]InstanceFactory),
+	}
+}
+
+// Register agrega un collector. La primera fuente registrada es la default.
+func (r *Registry) Register(name string, c Collector) {
+	if _, ok := r.collectors[name]; !ok {
+		r.names = append(r.names, name)
+	}
+	r.collectors[name] = c
+}
+
+// RegisterInstances permite usar la fuente name con cualquier instancia
+// ("gitlab:user@gitlab.example.com"), aunque no tenga una instancia default.
+func (r *Registry) RegisterInstances(name string, factory InstanceFactory) {
+	r.factories[name] = factory
+}
+
+// Get devuelve el collector de una fuente. Vacío = la fuente default.
+func (r *Registry) Get(name string) (Collector, error) {
+	if name == "" {
+		name = r.Default()
+	}
+	c, ok := r.collectors[name]
+	if !ok {
+		return nil, &UnknownSourceError{Name: name}
+	}
+	return c, nil
+}
+
+// Default devuelve el nombre de la fuente default.
+func (r *Registry) Default() string {
+	if len(r.names) == 0 {
+		return ""
+	}
+	return r.names[0]
+}
+
+// Names devuelve las fuentes en orden de registro.
+func (r *Registry) Names() []string {
+	return append([]string(nil), r.names...)
+}
+
+// UnknownSourceError indica que se pidió una fuente sin collector.
+type UnknownSourceError struct {
+	Name string
+}
+
+func (e *UnknownSourceError) Error() string {
+	return fmt.Sprintf("unknown profile source %q", e.Name)
+}
+
+// InvalidInputError indica una entrada que no se puede asociar a una fuente.
+type InvalidInputError struct {
+	Input  string
+	Reason string
+}
+
+func (e *InvalidInputError) Error() string {
+	return fmt.Sprintf("invalid profile input %q: %s", e.Input, e.Reason)
+}
+
+// sleepContext espera d o hasta que ctx se cancele, lo que pase primero.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
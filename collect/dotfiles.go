@@ -0,0 +1,350 @@
+package collect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"distroanalyzer/profile"
+	"distroanalyzer/taxonomy"
+)
+
+// dotfilesMaxRepos es cuántos repos de dotfiles se recorren como máximo:
+// cada uno cuesta un request al API de trees.
+const dotfilesMaxRepos = 2
+
+// Nombres y topics que identifican un repo de dotfiles.
+var (
+	dotfilesNames  = []string{"dotfiles", "dots", "rice", "config", "configs", "nixos-config", "nix-config", "home-manager"}
+	dotfilesTopics = []string{"dotfiles", "rice", "ricing", "unixporn", "home-manager", "nixos-config"}
+)
+
+// isDotfilesRepo indica si un repo parece de dotfiles por su nombre
+// ("dotfiles", ".dots", "hypr-dots", "nixos-config") o sus topics.
+func isDotfilesRepo(repo profile.RepoStats) bool {
+	name := strings.TrimPrefix(strings.ToLower(repo.Name), ".")
+	if containsString(dotfilesNames, name) || strings.Contains(name, "dotfiles") ||
+		strings.HasPrefix(name, "dots-") || strings.HasSuffix(name, "-dots") {
+		return true
+	}
+	for _, topic := range repo.Topics {
+		if containsString(dotfilesTopics, strings.ToLower(topic)) {
+			return true
+		}
+	}
+	return false
+}
+
+// setupField elige la lista de Setup donde va una detección.
+type setupField func(s *profile.Setup) *[]string
+
+var (
+	windowManager  setupField = func(s *profile.Setup) *[]string { return &s.WindowManagers }
+	shell          setupField = func(s *profile.Setup) *[]string { return &s.Shells }
+	shellPlugin    setupField = func(s *profile.Setup) *[]string { return &s.ShellPlugins }
+	packageManager setupField = func(s *profile.Setup) *[]string { return &s.PackageManagers }
+	distro         setupField = func(s *profile.Setup) *[]string { return &s.Distros }
+	tool           setupField = func(s *profile.Setup) *[]string { return &s.Tools }
+)
+
+// setupHint es lo que delata un archivo: un valor en un campo de Setup.
+type setupHint struct {
+	field setupField
+	value string
+}
+
+// dotfileRules asocia rutas con lo que delatan. Un patrón terminado en "/"
+// es un directorio en cualquier nivel; si no, es el final de la ruta
+// ("sway/config" coincide con ".config/sway/config").
+var dotfileRules = []struct {
+	pattern string
+	hints   []setupHint
+}{
+	// Window managers y compositores
+	{"hyprland.conf", []setupHint{{windowManager, "hyprland"}}},
+	{"sway/config", []setupHint{{windowManager, "sway"}}},
+	{"i3/config", []setupHint{{windowManager, "i3"}}},
+	{"bspwm/bspwmrc", []setupHint{{windowManager, "bspwm"}}},
+	{"awesome/rc.lua", []setupHint{{windowManager, "awesomewm"}}},
+	{"qtile/config.py", []setupHint{{windowManager, "qtile"}}},
+	{"dwm/config.h", []setupHint{{windowManager, "dwm"}}},
+	{".xinitrc", []setupHint{{tool, "x11"}}},
+
+	// Shells y sus plugins
+	{".zshrc", []setupHint{{shell, "zsh"}}},
+	{"zsh/", []setupHint{{shell, "zsh"}}},
+	{".oh-my-zsh/", []setupHint{{shell, "zsh"}, {shellPlugin, "oh-my-zsh"}}},
+	{".zsh_plugins.txt", []setupHint{{shell, "zsh"}, {shellPlugin, "antidote"}}},
+	{"zinit/", []setupHint{{shell, "zsh"}, {shellPlugin, "zinit"}}},
+	{".zimrc", []setupHint{{shell, "zsh"}, {shellPlugin, "zim"}}},
+	{".p10k.zsh", []setupHint{{shell, "zsh"}, {shellPlugin, "powerlevel10k"}}},
+	{"starship.toml", []setupHint{{shellPlugin, "starship"}}},
+	{".bashrc", []setupHint{{shell, "bash"}}},
+	{"fish/config.fish", []setupHint{{shell, "fish"}}},
+	{"nushell/config.nu", []setupHint{{shell, "nushell"}}},
+
+	// Gestores de paquetes y la distro que delatan
+	{"PKGBUILD", []setupHint{{packageManager, "pacman"}, {distro, "arch"}}},
+	{"pacman.conf", []setupHint{{packageManager, "pacman"}, {distro, "arch"}}},
+	{"makepkg.conf", []setupHint{{packageManager, "pacman"}, {distro, "arch"}}},
+	{"paru.conf", []setupHint{{packageManager, "pacman"}, {distro, "arch"}}},
+	{"sources.list", []setupHint{{packageManager, "apt"}, {distro, "debian"}}},
+	{"sources.list.d/", []setupHint{{packageManager, "apt"}, {distro, "debian"}}},
+	{"dnf.conf", []setupHint{{packageManager, "dnf"}, {distro, "fedora"}}},
+	{"yum.repos.d/", []setupHint{{packageManager, "dnf"}, {distro, "fedora"}}},
+	{"portage/", []setupHint{{packageManager, "portage"}, {distro, "gentoo"}}},
+	{"xbps.d/", []setupHint{{packageManager, "xbps"}, {distro, "void"}}},
+	{"apk/world", []setupHint{{packageManager, "apk"}, {distro, "alpine"}}},
+	{"zypp/", []setupHint{{packageManager, "zypper"}, {distro, "opensuse"}}},
+
+	// Nix
+	{"configuration.nix", []setupHint{{packageManager, "nix"}, {distro, "nixos"}}},
+	{"hardware-configuration.nix", []setupHint{{packageManager, "nix"}, {distro, "nixos"}}},
+	{"flake.nix", []setupHint{{packageManager, "nix"}, {tool, "nix-flakes"}}},
+	{"home.nix", []setupHint{{packageManager, "nix"}, {tool, "home-manager"}}},
+	{"home-manager/", []setupHint{{packageManager, "nix"}, {tool, "home-manager"}}},
+}
+
+// dotfileParsers leen el contenido de archivos que delatan más que su
+// nombre: el WM que arranca .xinitrc, los plugins que carga .zshrc. Cada
+// archivo cuesta un request, así que se lee uno por parser y repo.
+var dotfileParsers = []struct {
+	pattern string
+	parse   func(content string) []setupHint
+}{
+	{".xinitrc", parseXinitrc},
+	{".zshrc", parseZshrc},
+}
+
+// xinitWrappers son comandos que .xinitrc antepone al WM en la línea exec.
+var xinitWrappers = []string{"dbus-launch", "dbus-run-session", "ssh-agent", "env"}
+
+// wmBinaries son los WMs cuyo ejecutable no es su término de la taxonomía.
+var wmBinaries = map[string]string{"awesome": "awesomewm"}
+
+// parseXinitrc busca el WM en la línea "exec" de .xinitrc
+// ("exec dbus-launch --exit-with-session i3" → i3).
+func parseXinitrc(content string) []setupHint {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "exec" {
+			continue
+		}
+		for _, field := range fields[1:] {
+			name := path.Base(field)
+			if strings.HasPrefix(name, "-") || strings.Contains(name, "=") || containsString(xinitWrappers, name) {
+				continue
+			}
+			if id, ok := wmBinaries[name]; ok {
+				name = id
+			}
+			if id, ok := taxonomy.Default().Canonical(name); ok && taxonomy.Default().IsA(id, "window-manager") {
+				return []setupHint{{windowManager, id}}
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// zshMaxPlugins acota los plugins que se toman de un .zshrc.
+const zshMaxPlugins = 20
+
+// parseZshrc lee los plugins de .zshrc: la lista plugins=(...) de
+// oh-my-zsh y las líneas "zinit light/load" y "antigen bundle".
+func parseZshrc(content string) []setupHint {
+	var hints []setupHint
+	add := func(value string) {
+		value = strings.TrimSuffix(path.Base(strings.Trim(value, `"'`)), ".git")
+		if value == "" || value == "." || len(hints) >= zshMaxPlugins {
+			return
+		}
+		for _, h := range hints {
+			if h.value == value {
+				return
+			}
+		}
+		hints = append(hints, setupHint{shellPlugin, value})
+	}
+
+	inPlugins := false
+	for _, line := range strings.Split(content, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+
+		// plugins=(git docker) puede seguir en varias líneas hasta ")"
+		if !inPlugins && strings.HasPrefix(strings.TrimSpace(line), "plugins=(") {
+			add("oh-my-zsh")
+			inPlugins = true
+			fields = strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "plugins=("))
+		}
+		if inPlugins {
+			for _, field := range fields {
+				name, closed := strings.CutSuffix(field, ")")
+				add(name)
+				if closed {
+					inPlugins = false
+					break
+				}
+			}
+			continue
+		}
+
+		switch {
+		case len(fields) >= 3 && fields[0] == "zinit" && (fields[1] == "light" || fields[1] == "load"):
+			add("zinit")
+			add(fields[2])
+		case len(fields) >= 3 && fields[0] == "antigen" && fields[1] == "bundle":
+			add("antigen")
+			add(fields[2])
+		}
+	}
+
+	if len(hints) == 0 {
+		return nil
+	}
+	return append([]setupHint{{shell, "zsh"}}, hints...)
+}
+
+// applyHints agrega hints a setup sin repetir valores.
+func applyHints(setup *profile.Setup, hints []setupHint) {
+	for _, hint := range hints {
+		list := hint.field(setup)
+		if !containsString(*list, hint.value) {
+			*list = append(*list, hint.value)
+		}
+	}
+}
+
+// detectSetup aplica dotfileRules a las rutas de un repo y agrega lo
+// encontrado a setup. Cada regla aporta un solo archivo como evidencia,
+// aunque un directorio coincida con cientos. Devuelve si hubo alguna
+// coincidencia.
+func detectSetup(setup *profile.Setup, repo string, paths []string) bool {
+	matched := make([]bool, len(dotfileRules))
+	found := false
+	for _, path := range paths {
+		for i, rule := range dotfileRules {
+			if matched[i] || !matchesDotfile(path, rule.pattern) {
+				continue
+			}
+			matched[i], found = true, true
+			applyHints(setup, rule.hints)
+			setup.Files = append(setup.Files, repo+"/"+path)
+		}
+	}
+	return found
+}
+
+// matchesDotfile compara una ruta con un patrón de dotfileRules.
+func matchesDotfile(path, pattern string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		return strings.Contains("/"+path+"/", "/"+pattern)
+	}
+	return strings.HasSuffix("/"+path, "/"+pattern)
+}
+
+// fetchSetup recorre los repos de dotfiles del usuario y arma su Setup.
+// Devuelve nil si no hay repos de dotfiles o no se detectó nada.
+func (g *GitHubCollector) fetchSetup(ctx context.Context, username string, repos []profile.RepoStats) *profile.Setup {
+	setup := &profile.Setup{}
+	walked := 0
+	for _, repo := range repos {
+		if walked >= dotfilesMaxRepos {
+			break
+		}
+		if !isDotfilesRepo(repo) {
+			continue
+		}
+		walked++
+
+		paths, err := g.fetchTree(ctx, username, repo.Name)
+		if err != nil {
+			continue
+		}
+		found := detectSetup(setup, repo.Name, paths)
+		if g.parseDotfiles(ctx, setup, username, repo.Name, paths) {
+			found = true
+		}
+		if found {
+			setup.Repos = append(setup.Repos, repo.Name)
+		}
+	}
+
+	if len(setup.Repos) == 0 {
+		return nil
+	}
+	return setup
+}
+
+// parseDotfiles lee los archivos de dotfileParsers que haya en paths y
+// agrega lo que delata su contenido. Devuelve si hubo alguna coincidencia.
+func (g *GitHubCollector) parseDotfiles(ctx context.Context, setup *profile.Setup, username, repo string, paths []string) bool {
+	found := false
+	for _, parser := range dotfileParsers {
+		for _, p := range paths {
+			if !matchesDotfile(p, parser.pattern) {
+				continue
+			}
+			content, err := g.fetchFile(ctx, username, repo, p)
+			if err == nil {
+				if hints := parser.parse(content); len(hints) > 0 {
+					applyHints(setup, hints)
+					if file := repo + "/" + p; !containsString(setup.Files, file) {
+						setup.Files = append(setup.Files, file)
+					}
+					found = true
+				}
+			}
+			break
+		}
+	}
+	return found
+}
+
+// fetchFile descarga el contenido de un archivo de la rama default.
+func (g *GitHubCollector) fetchFile(ctx context.Context, username, repo, file string) (string, error) {
+	segments := strings.Split(file, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	body, err := g.get(ctx, fmt.Sprintf("%s/repos/%s/%s/contents/%s",
+		g.opts.APIURL, url.PathEscape(username), url.PathEscape(repo), strings.Join(segments, "/")), githubRaw)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// fetchTree lista todas las rutas (archivos y directorios) de la rama
+// default de un repo con el API de trees.
+func (g *GitHubCollector) fetchTree(ctx context.Context, username, repo string) ([]string, error) {
+	body, err := g.get(ctx, fmt.Sprintf("%s/repos/%s/%s/git/trees/HEAD?recursive=1",
+		g.opts.APIURL, url.PathEscape(username), url.PathEscape(repo)), githubJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Si el árbol es enorme la API lo trunca; lo recibido alcanza igual
+	var tree githubTree
+	if err := json.Unmarshal(body, &tree); err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, len(tree.Tree))
+	for i, entry := range tree.Tree {
+		paths[i] = entry.Path
+	}
+	return paths, nil
+}
+
+// githubTree representa la respuesta del API de trees de GitHub.
+type githubTree struct {
+	Tree []struct {
+		Path string `json:"path"`
+	} `json:"tree"`
+	Truncated bool `json:"truncated"`
+}
@@ -0,0 +1,93 @@
+package collect
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"distroanalyzer/profile"
+)
+
+func TestParseXinitrc(t *testing.T) {
+	for content, want := range map[string]string{
+		"xrdb -merge ~/.Xresources\nexec dbus-launch --exit-with-session i3\n": "i3",
+		"exec /usr/bin/bspwm":              "bspwm",
+		"# exec dwm\nexec awesome":         "awesomewm",
+		"exec startplasma-x11":             "",
+		"setxkbmap latam\nxset r rate 200": "",
+	} {
+		setup := &profile.Setup{}
+		applyHints(setup, parseXinitrc(content))
+		got := ""
+		if len(setup.WindowManagers) > 0 {
+			got = setup.WindowManagers[0]
+		}
+		if got != want {
+			t.Errorf("parseXinitrc(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
+
+func TestParseZshrc(t *testing.T) {
+	content := `export ZSH="$HOME/.oh-my-zsh"
+plugins=(
+  git docker # contenedores
+  zsh-autosuggestions
+)
+source $ZSH/oh-my-zsh.sh
+zinit light zdharma-continuum/fast-syntax-highlighting
+antigen bundle zsh-users/zsh-completions
+# zinit light commented/out
+`
+	setup := &profile.Setup{}
+	applyHints(setup, parseZshrc(content))
+
+	want := []string{"oh-my-zsh", "git", "docker", "zsh-autosuggestions", "zinit",
+		"fast-syntax-highlighting", "antigen", "zsh-completions"}
+	if len(setup.ShellPlugins) != len(want) {
+		t.Fatalf("ShellPlugins = %v, want %v", setup.ShellPlugins, want)
+	}
+	for i := range want {
+		if setup.ShellPlugins[i] != want[i] {
+			t.Errorf("ShellPlugins = %v, want %v", setup.ShellPlugins, want)
+			break
+		}
+	}
+	if len(setup.Shells) != 1 || setup.Shells[0] != "zsh" {
+		t.Errorf("Shells = %v, want [zsh]", setup.Shells)
+	}
+
+	if hints := parseZshrc("alias ll='ls -l'\n"); hints != nil {
+		t.Errorf("zshrc without plugins gave %v", hints)
+	}
+}
+
+func TestFetchSetupReadsDotfiles(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/alice/dotfiles/git/trees/HEAD":
+			fmt.Fprint(w, `{"tree":[{"path":"x11/.xinitrc"},{"path":".zshrc"},{"path":"pacman.conf"}]}`)
+		case "/repos/alice/dotfiles/contents/x11/.xinitrc":
+			fmt.Fprint(w, "exec sway")
+		case "/repos/alice/dotfiles/contents/.zshrc":
+			fmt.Fprint(w, "plugins=(git)")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	g := NewGitHubCollector("", GitHubOptions{APIURL: srv.URL})
+	setup := g.fetchSetup(context.Background(), "alice", []profile.RepoStats{{Name: "dotfiles"}})
+	if setup == nil {
+		t.Fatal("no setup detected")
+	}
+	if fmt.Sprint(setup.WindowManagers) != "[sway]" || fmt.Sprint(setup.Tools) != "[x11]" {
+		t.Errorf("WindowManagers = %v, Tools = %v", setup.WindowManagers, setup.Tools)
+	}
+	if fmt.Sprint(setup.ShellPlugins) != "[oh-my-zsh git]" {
+		t.Errorf("ShellPlugins = %v", setup.ShellPlugins)
+	}
+}
@@ -0,0 +1,48 @@
+package collect
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUserNotFound indica que el usuario no existe en la fuente.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrRateLimited indica que la fuente rechazó el pedido por rate limit.
+// Los errores concretos son *RateLimitError, que dice cuándo reintentar.
+var ErrRateLimited = errors.New("rate limited")
+
+// ErrRobotsDisallowed indica que el robots.txt del sitio no nos permite
+// leer la página.
+var ErrRobotsDisallowed = errors.New("disallowed by robots.txt")
+
+// ErrBlockedAddress indica que la URL apunta a una dirección privada o
+// local y la configuración no lo permite.
+var ErrBlockedAddress = errors.New("address not allowed")
+
+// errNotFound es el 404 de una API; cada collector lo traduce a
+// ErrUserNotFound cuando corresponde al usuario.
+var errNotFound = errors.New("resource not found")
+
+// RateLimitError indica que se agotó el rate limit de una fuente.
+type RateLimitError struct {
+	Source string
+	Reset  time.Time // cuándo se puede volver a intentar
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s rate limit exceeded, resets at %s", e.Source, e.Reset.UTC().Format(time.RFC3339))
+}
+
+// Is permite errors.Is(err, ErrRateLimited).
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RetryAfter devuelve cuánto falta para el reset, redondeado hacia arriba
+// al segundo y nunca menor a uno.
+func (e *RateLimitError) RetryAfter(now time.Time) time.Duration {
+	wait := e.Reset.Sub(now).Truncate(time.Second) + time.Second
+	return max(wait, time.Second)
+}
@@ -0,0 +1,60 @@
+package collect
+
+import (
+	"container/list"
+	"sync"
+)
+
+// etagCache guarda las últimas respuestas con ETag para repetir el pedido
+// con If-None-Match: un 304 no consume rate limit y no trae el cuerpo.
+// Cuando se llena descarta la entrada usada hace más tiempo.
+type etagCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // más reciente al frente
+	entries  map[string]*list.Element
+}
+
+type etagEntry struct {
+	key  string
+	etag string
+	body []byte
+}
+
+func newETagCache(capacity int) *etagCache {
+	return &etagCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *etagCache) get(key string) (etagEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return etagEntry{}, false
+	}
+	c.order.MoveToFront(el)
+	return *el.Value.(*etagEntry), true
+}
+
+func (c *etagCache) put(key, etag string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value = &etagEntry{key: key, etag: etag, body: body}
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&etagEntry{key: key, etag: etag, body: body})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*etagEntry).key)
+	}
+}
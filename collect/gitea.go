@@ -0,0 +1,306 @@
+package collect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"distroanalyzer/profile"
+)
+
+// DefaultCodebergURL es Codeberg, la instancia pública de Forgejo más usada.
+const DefaultCodebergURL = "https://codeberg.org"
+
+// readmeNames son los archivos que se prueban, en orden, para el README.
+var readmeNames = []string{"README.md", "README", "readme.md", "README.rst", "README.txt"}
+
+const (
+	giteaPageSize = 50  // máximo por defecto de la API
+	giteaMaxRepos = 100 // repos que se recorren como máximo, paginando
+
+	// Los lenguajes cuestan un request por repo: solo se piden para los
+	// mejor rankeados.
+	giteaLanguageRepos = 5
+)
+
+// GiteaCollector recolecta datos de perfiles públicos de cualquier
+// instancia compatible con la API de Gitea: Gitea, Forgejo o Codeberg.
+type GiteaCollector struct {
+	client  *http.Client
+	baseURL string // URL de la instancia, sin /api/v1
+	token   string // Token opcional para perfiles y rate limits
+}
+
+// NewGiteaCollector crea un collector para la instancia en baseURL.
+// Vacío = Codeberg. El token es opcional.
+func NewGiteaCollector(baseURL, token string) *GiteaCollector {
+	if baseURL == "" {
+		baseURL = DefaultCodebergURL
+	}
+	return &GiteaCollector{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+	}
+}
+
+// NewGiteaInstanceCollector crea un collector sin token para una instancia
+// elegida por el usuario ("forgejo:alice@git.example.com"), que solo
+// conecta a direcciones públicas.
+func NewGiteaInstanceCollector(baseURL string) *GiteaCollector {
+	g := NewGiteaCollector(baseURL, "")
+	g.client.Transport = newWebTransport(false)
+	return g
+}
+
+// Collect obtiene bio, website, repos, sus topics y lenguajes, y el README
+// del repo más relevante del usuario.
+func (g *GiteaCollector) Collect(ctx context.Context, username string) (*profile.RawData, error) {
+	var user giteaUser
+	err := g.get(ctx, "/users/"+url.PathEscape(username), &user)
+	if errors.Is(err, errNotFound) {
+		return nil, fmt.Errorf("gitea user %q: %w", username, ErrUserNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := g.fetchRepos(ctx, username)
+	if errors.Is(err, ErrRateLimited) {
+		return nil, err
+	}
+	if err != nil {
+		repos = []giteaRepo{}
+	}
+
+	stats := make([]profile.RepoStats, 0, len(repos))
+	for _, r := range repos {
+		stats = append(stats, profile.RepoStats{
+			Name:     r.Name,
+			Language: r.Language,
+			Topics:   r.Topics,
+			Fork:     r.Fork,
+			Stars:    r.StarsCount,
+			PushedAt: r.UpdatedAt,
+		})
+	}
+	rankRepos(stats, time.Now())
+
+	// Los forks quedan en Repos, marcados, pero no aportan señales
+	own, forks := splitForks(stats)
+	for i := range own[:min(len(own), giteaLanguageRepos)] {
+		own[i].Languages = g.fetchLanguages(ctx, username, own[i].Name)
+	}
+	names, topics := repoNamesAndTopics(own)
+
+	data := &profile.RawData{
+		Bio:          user.Description,
+		Repositories: names,
+		Topics:       topics,
+		Repos:        append(own, forks...),
+		Website:      user.Website,
+		Location:     user.Location,
+		Email:        user.Email,
+		ReadmeText:   g.fetchReadme(ctx, username, mostStarred(repos)),
+	}
+	data.Languages = repoLanguages(data)
+
+	// Si se canceló, fetchReadme devolvió nil sin que falte el README
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// fetchRepos pagina los repos del usuario hasta giteaMaxRepos, sin los
+// archivados.
+func (g *GiteaCollector) fetchRepos(ctx context.Context, username string) ([]giteaRepo, error) {
+	var repos []giteaRepo
+	fetched := 0
+	for page := 1; fetched < giteaMaxRepos; page++ {
+		var batch []giteaRepo
+		path := fmt.Sprintf("/users/%s/repos?limit=%d&page=%d", url.PathEscape(username), giteaPageSize, page)
+		header, err := g.getWithHeader(ctx, path, &batch)
+		if err != nil {
+			// Si falla una página después de la primera, sirve lo ya juntado
+			if page > 1 {
+				break
+			}
+			return nil, err
+		}
+
+		fetched += len(batch)
+		for _, r := range batch {
+			if !r.Archived {
+				repos = append(repos, r)
+			}
+		}
+		if len(batch) == 0 || !giteaHasNext(header, fetched, len(batch)) {
+			break
+		}
+	}
+	return repos[:min(len(repos), giteaMaxRepos)], nil
+}
+
+// giteaHasNext indica si hay otra página: lo dice el header Link; si no
+// viene, X-Total-Count; y si tampoco, una página llena.
+func giteaHasNext(h http.Header, fetched, batch int) bool {
+	if link := h.Get("Link"); link != "" {
+		return strings.Contains(link, `rel="next"`)
+	}
+	if total, err := strconv.Atoi(h.Get("X-Total-Count")); err == nil {
+		return fetched < total
+	}
+	return batch == giteaPageSize
+}
+
+// fetchLanguages obtiene los bytes de código por lenguaje de un repo.
+// Devuelve nil si falla: el lenguaje principal sigue disponible.
+func (g *GiteaCollector) fetchLanguages(ctx context.Context, username, repo string) map[string]int {
+	var languages map[string]int
+	path := fmt.Sprintf("/repos/%s/%s/languages", url.PathEscape(username), url.PathEscape(repo))
+	if err := g.get(ctx, path, &languages); err != nil {
+		return nil
+	}
+	return languages
+}
+
+// mostStarred elige el repo propio (no fork) con más estrellas.
+func mostStarred(repos []giteaRepo) *giteaRepo {
+	var best *giteaRepo
+	for i := range repos {
+		if repos[i].Fork {
+			continue
+		}
+		if best == nil || repos[i].StarsCount > best.StarsCount {
+			best = &repos[i]
+		}
+	}
+	return best
+}
+
+// fetchReadme prueba los nombres usuales del README en la rama default.
+func (g *GiteaCollector) fetchReadme(ctx context.Context, username string, repo *giteaRepo) *string {
+	if repo == nil {
+		return nil
+	}
+
+	for _, name := range readmeNames {
+		endpoint := fmt.Sprintf("%s/api/v1/repos/%s/%s/raw/%s",
+			g.baseURL, url.PathEscape(username), url.PathEscape(repo.Name), name)
+		if repo.DefaultBranch != "" {
+			endpoint += "?ref=" + url.QueryEscape(repo.DefaultBranch)
+		}
+		if text := g.fetchRaw(ctx, endpoint); text != nil {
+			return text
+		}
+	}
+	return nil
+}
+
+func (g *GiteaCollector) fetchRaw(ctx context.Context, endpoint string) *string {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil
+	}
+	g.setHeaders(req)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil
+	}
+
+	text := string(body)
+	return &text
+}
+
+// get hace un GET a la API v1 y decodifica la respuesta JSON en v.
+func (g *GiteaCollector) get(ctx context.Context, path string, v interface{}) error {
+	_, err := g.getWithHeader(ctx, path, v)
+	return err
+}
+
+// getWithHeader es get, pero devuelve también los headers de la respuesta
+// (la paginación viaja en Link y X-Total-Count). Como en GitHub, un 403
+// con la cuota agotada es *RateLimitError.
+func (g *GiteaCollector) getWithHeader(ctx context.Context, path string, v interface{}) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", g.baseURL+"/api/v1"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	g.setHeaders(req)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errNotFound
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if isRateLimited(resp.StatusCode, resp.Header, body) {
+			return nil, &RateLimitError{Source: "gitea", Reset: time.Now().Add(rateLimitWait(resp.Header, 0, time.Now()))}
+		}
+		return nil, fmt.Errorf("gitea API returned status %d", resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("gitea API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, fmt.Errorf("invalid gitea API response: %w", err)
+	}
+	return resp.Header, nil
+}
+
+// setHeaders configura headers comunes para requests a la API de Gitea.
+func (g *GiteaCollector) setHeaders(req *http.Request) {
+	req.Header.Set("Accept", "application/json")
+	if g.token != "" {
+		req.Header.Set("Authorization", "token "+g.token)
+	}
+}
+
+// giteaUser representa la respuesta de la API de Gitea para un usuario.
+type giteaUser struct {
+	Login       string `json:"login"`
+	Description string `json:"description"` // la bio del perfil
+	Website     string `json:"website"`
+	Location    string `json:"location"`
+	Email       string `json:"email"`
+}
+
+// giteaRepo representa un repositorio en la respuesta de Gitea.
+type giteaRepo struct {
+	Name          string   `json:"name"`
+	Language      string   `json:"language"`
+	Topics        []string `json:"topics"`
+	Fork          bool     `json:"fork"`
+	Archived      bool     `json:"archived"`
+	StarsCount    int      `json:"stars_count"`
+	DefaultBranch string   `json:"default_branch"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
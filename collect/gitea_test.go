@@ -0,0 +1,129 @@
+package collect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newGiteaStub levanta una API v1 mínima con el usuario "alice", dos
+// páginas de repos (una con un fork), sus lenguajes y un README sin
+// extensión.
+func newGiteaStub(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/users/alice", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"login":"alice","description":"Kernel hacker","website":"https://alice.dev","location":"Rosario"}`)
+	})
+	mux.HandleFunc("/api/v1/users/alice/repos", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total-Count", "3")
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next", <`+r.URL.Path+`?page=2>; rel="last"`)
+			fmt.Fprint(w, `[{"name":"dotfiles","language":"Nix","topics":["nixos"],"stars_count":1}]`)
+		case "2":
+			w.Header().Set("Link", `<`+r.URL.Path+`?page=1>; rel="prev", <`+r.URL.Path+`?page=1>; rel="first"`)
+			fmt.Fprint(w, `[{"name":"kernel-patches","language":"C","topics":["linux"],"stars_count":9,"default_branch":"main"},
+				{"name":"linux","language":"Java","topics":["kernel"],"stars_count":500,"fork":true}]`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+	mux.HandleFunc("/api/v1/repos/alice/dotfiles/languages", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"Nix":900,"Shell":100}`)
+	})
+	mux.HandleFunc("/api/v1/repos/alice/kernel-patches/languages", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"C":9500,"Shell":500}`)
+	})
+	mux.HandleFunc("/api/v1/repos/alice/linux/languages", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("languages requested for a fork")
+	})
+	mux.HandleFunc("/api/v1/repos/alice/kernel-patches/raw/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/alice/kernel-patches/raw/README" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("ref") != "main" {
+			t.Errorf("README ref = %q, want main", r.URL.Query().Get("ref"))
+		}
+		fmt.Fprint(w, "kernel-patches")
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGiteaCollect(t *testing.T) {
+	srv := newGiteaStub(t)
+	data, err := NewGiteaCollector(srv.URL, "").Collect(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data.Bio != "Kernel hacker" || data.Website != "https://alice.dev" || data.Location != "Rosario" {
+		t.Errorf("user fields = %q, %q, %q", data.Bio, data.Website, data.Location)
+	}
+	if got := fmt.Sprint(data.Repositories); got != "[kernel-patches dotfiles]" {
+		t.Errorf("Repositories = %s, want both pages ranked, without the fork", got)
+	}
+	if len(data.Repos) != 3 || !data.Repos[2].Fork || data.Repos[2].Name != "linux" {
+		t.Errorf("Repos = %+v, want the fork recorded last with Fork set", data.Repos)
+	}
+	if got := fmt.Sprint(data.Topics); got != "[linux nixos]" {
+		t.Errorf("Topics = %s, want no topics from the fork", got)
+	}
+	if got := fmt.Sprint(data.Languages); got != "[C Nix Shell]" {
+		t.Errorf("Languages = %s, want ordered by bytes without the fork", got)
+	}
+	if data.ReadmeText == nil || *data.ReadmeText != "kernel-patches" {
+		t.Errorf("ReadmeText = %v, want the README fallback of the most starred own repo", data.ReadmeText)
+	}
+}
+
+func TestGiteaCollectUserNotFound(t *testing.T) {
+	srv := newGiteaStub(t)
+	_, err := NewGiteaCollector(srv.URL, "").Collect(context.Background(), "bob")
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("err = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestGiteaCollectRateLimited(t *testing.T) {
+	tests := map[string]http.HandlerFunc{
+		"429": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+		"403 without quota": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+		},
+	}
+	for name, handler := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+
+			_, err := NewGiteaCollector(srv.URL, "").Collect(context.Background(), "alice")
+			if !errors.Is(err, ErrRateLimited) {
+				t.Errorf("err = %v, want ErrRateLimited", err)
+			}
+		})
+	}
+}
+
+func TestGiteaCollectForbiddenIsNotRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	_, err := NewGiteaCollector(srv.URL, "").Collect(context.Background(), "alice")
+	if err == nil || errors.Is(err, ErrRateLimited) {
+		t.Errorf("err = %v, want a plain status error", err)
+	}
+}
@@ -1,54 +1,143 @@
 package collect
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"distroanalyzer/profile"
 )
 
+// DefaultGitHubAPI es la API pública de GitHub.
+const DefaultGitHubAPI = "https://api.github.com"
+
+// GitHubOptions ajusta cuánto recolecta GitHubCollector. Los campos en cero
+// usan los valores por defecto.
+type GitHubOptions struct {
+	// APIURL permite usar GitHub Enterprise. Vacío = api.github.com.
+	APIURL string
+
+	// MaxRepos es cuántos repos propios (sin forks ni archivados) se
+	// recolectan, paginando de 100 en 100.
+	MaxRepos int
+
+	// MaxReadmes es cuántos READMEs se agregan, sin contar el del perfil.
+	MaxReadmes int
+
+	// ReadmeBudget es el máximo de bytes de README agregado.
+	ReadmeBudget int
+
+	// RateLimitMaxWait es cuánto se espera como máximo a que se libere el
+	// rate limit antes de fallar con *RateLimitError. Negativo = no esperar.
+	RateLimitMaxWait time.Duration
+
+	// ETagCacheSize es cuántas respuestas se guardan para pedidos
+	// condicionales.
+	ETagCacheSize int
+
+	// LanguageRepos es de cuántos repos, los mejor rankeados, se piden los
+	// bytes por lenguaje: cuesta un request por repo. Cero = el default,
+	// que sin token es no pedirlos; negativo = no pedirlos.
+	LanguageRepos int
+}
+
+// Valores por defecto de GitHubOptions.
+const (
+	DefaultGitHubMaxRepos         = 100
+	DefaultGitHubMaxReadmes       = 3
+	DefaultGitHubReadmeBudget     = 16 * 1024
+	DefaultGitHubRateLimitMaxWait = 10 * time.Second
+	DefaultGitHubETagCacheSize    = 1024
+	DefaultGitHubLanguageRepos    = 5
+)
+
+const (
+	githubPageSize   = 100 // máximo que acepta la API
+	githubMaxRetries = 3   // reintentos por rate limit de un mismo pedido
+
+	githubJSON = "application/vnd.github.v3+json"
+	githubRaw  = "application/vnd.github.v3.raw"
+)
+
 // GitHubCollector recolecta datos de perfiles públicos de GitHub.
+//
+// Todos los pedidos pasan por get, que reutiliza respuestas con ETag y
+// respeta los headers de rate limit: espera si el reset está cerca y si no
+// falla con *RateLimitError sin gastar más pedidos.
 type GitHubCollector struct {
 	client *http.Client
 	token  string // Token opcional para aumentar rate limits
+	opts   GitHubOptions
+	etags  *etagCache
+	sleep  func(context.Context, time.Duration) error
+
+	// Último estado de rate limit informado por la API
+	mu        sync.Mutex
+	remaining int // -1 = desconocido
+	reset     time.Time
 }
 
 // NewGitHubCollector crea un nuevo collector para GitHub.
 // El token es opcional pero recomendado para evitar rate limits (60 req/h sin auth).
-func NewGitHubCollector(token string) *GitHubCollector {
+func NewGitHubCollector(token string, opts GitHubOptions) *GitHubCollector {
+	if opts.APIURL == "" {
+		opts.APIURL = DefaultGitHubAPI
+	}
+	opts.APIURL = strings.TrimSuffix(opts.APIURL, "/")
+	if opts.MaxRepos <= 0 {
+		opts.MaxRepos = DefaultGitHubMaxRepos
+	}
+	if opts.MaxReadmes <= 0 {
+		opts.MaxReadmes = DefaultGitHubMaxReadmes
+	}
+	if opts.ReadmeBudget <= 0 {
+		opts.ReadmeBudget = DefaultGitHubReadmeBudget
+	}
+	if opts.RateLimitMaxWait == 0 {
+		opts.RateLimitMaxWait = DefaultGitHubRateLimitMaxWait
+	} else if opts.RateLimitMaxWait < 0 {
+		opts.RateLimitMaxWait = 0
+	}
+	if opts.ETagCacheSize <= 0 {
+		opts.ETagCacheSize = DefaultGitHubETagCacheSize
+	}
+	// Sin token hay 60 requests por hora: se usa solo el lenguaje principal
+	switch {
+	case opts.LanguageRepos == 0 && token != "":
+		opts.LanguageRepos = DefaultGitHubLanguageRepos
+	case opts.LanguageRepos < 0:
+		opts.LanguageRepos = 0
+	}
+
 	return &GitHubCollector{
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		token: token,
+		token:     token,
+		opts:      opts,
+		etags:     newETagCache(opts.ETagCacheSize),
+		sleep:     sleepContext,
+		remaining: -1,
 	}
 }
 
-// Collect obtiene bio, repos y README del usuario de GitHub.
-func (g *GitHubCollector) Collect(username string) (*profile.RawData, error) {
-	userURL := fmt.Sprintf("https://api.github.com/users/%s", username)
-
-	req, err := http.NewRequest("GET", userURL, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	g.setHeaders(req)
-
-	resp, err := g.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("github API returned status %d", resp.StatusCode)
+// Collect obtiene bio, repos y READMEs del usuario de GitHub.
+func (g *GitHubCollector) Collect(ctx context.Context, username string) (*profile.RawData, error) {
+	body, err := g.get(ctx, fmt.Sprintf("%s/users/%s", g.opts.APIURL, url.PathEscape(username)), githubJSON)
+	if errors.Is(err, errNotFound) {
+		return nil, fmt.Errorf("github user %q: %w", username, ErrUserNotFound)
 	}
-
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
@@ -58,85 +147,219 @@ func (g *GitHubCollector) Collect(username string) (*profile.RawData, error) {
 		return nil, err
 	}
 
-	repos, err := g.fetchRepos(username)
+	repos, err := g.fetchRepos(ctx, username)
+	if errors.Is(err, ErrRateLimited) {
+		// Un perfil sin repos se cachearía como si fuera real
+		return nil, err
+	}
 	if err != nil {
-		repos = []string{}
+		repos = []profile.RepoStats{}
 	}
+	rankRepos(repos, time.Now())
 
-	readme := g.fetchReadme(username, repos)
+	// Los forks quedan en Repos, marcados, pero no aportan señales
+	own, forks := splitForks(repos)
+	for i := range own[:min(len(own), g.opts.LanguageRepos)] {
+		own[i].Languages = g.fetchLanguages(ctx, username, own[i].Name)
+	}
+	names, topics := repoNamesAndTopics(own)
 
-	return &profile.RawData{
+	data := &profile.RawData{
 		Bio:          user.Bio,
-		Repositories: repos,
+		Repositories: names,
+		Topics:       topics,
+		Repos:        append(own, forks...),
 		Website:      user.Blog,
 		Location:     user.Location,
 		Email:        user.Email,
-		ReadmeText:   readme,
-	}, nil
-}
-
-func (g *GitHubCollector) fetchRepos(username string) ([]string, error) {
-	url := fmt.Sprintf("https://api.github.com/users/%s/repos?sort=updated&per_page=10", username)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
+		ReadmeText:   g.collectReadmes(ctx, username, own),
+		Setup:        g.fetchSetup(ctx, username, own),
 	}
+	data.Languages = repoLanguages(data)
 
-	g.setHeaders(req)
-
-	resp, err := g.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
+	// Con ctx cancelado los pedidos opcionales fallan en silencio: no
+	// devolver un perfil a medias
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
+	return data, nil
+}
 
-	var repos []githubRepo
-	if err := json.Unmarshal(body, &repos); err != nil {
-		return nil, err
+// splitForks separa los repos propios de los forks, conservando el orden.
+func splitForks(repos []profile.RepoStats) (own, forks []profile.RepoStats) {
+	for _, r := range repos {
+		if r.Fork {
+			forks = append(forks, r)
+		} else {
+			own = append(own, r)
+		}
 	}
+	return own, forks
+}
 
-	names := make([]string, len(repos))
+// repoNamesAndTopics lista los nombres de repos y sus topics sin duplicados.
+func repoNamesAndTopics(repos []profile.RepoStats) (names, topics []string) {
+	names = make([]string, len(repos))
 	for i, r := range repos {
 		names[i] = r.Name
+		for _, topic := range r.Topics {
+			if !containsString(topics, topic) {
+				topics = append(topics, topic)
+			}
+		}
 	}
+	return names, topics
+}
 
-	return names, nil
+// repoLanguages lista los lenguajes de los repos propios: primero los
+// medidos en bytes, de mayor a menor, y después los principales sin
+// estadísticas.
+func repoLanguages(data *profile.RawData) []string {
+	var languages []string
+	for _, share := range data.LanguageShares() {
+		languages = append(languages, share.Language)
+	}
+	for _, r := range data.Repos {
+		if !r.Fork && r.Language != "" && !containsString(languages, r.Language) {
+			languages = append(languages, r.Language)
+		}
+	}
+	return languages
 }
 
-func (g *GitHubCollector) fetchReadme(username string, repos []string) *string {
-	if len(repos) == 0 {
-		return nil
+// fetchRepos pagina los repos del usuario, del push más reciente al más
+// viejo, hasta juntar MaxRepos propios. Los forks se guardan marcados, hasta
+// otros MaxRepos; los archivados no: no reflejan lo que el usuario usa hoy.
+func (g *GitHubCollector) fetchRepos(ctx context.Context, username string) ([]profile.RepoStats, error) {
+	var stats []profile.RepoStats
+	own, forks := 0, 0
+
+	for page := 1; own < g.opts.MaxRepos; page++ {
+		endpoint := fmt.Sprintf("%s/users/%s/repos?type=owner&sort=pushed&per_page=%d&page=%d",
+			g.opts.APIURL, url.PathEscape(username), githubPageSize, page)
+
+		var repos []githubRepo
+		body, err := g.get(ctx, endpoint, githubJSON)
+		if err == nil {
+			err = json.Unmarshal(body, &repos)
+		}
+		if err != nil {
+			// Si falla una página después de la primera, sirve lo ya juntado
+			if page > 1 {
+				break
+			}
+			return nil, err
+		}
+
+		for _, r := range repos {
+			count := &own
+			if r.Fork {
+				count = &forks
+			}
+			if r.Archived || *count >= g.opts.MaxRepos {
+				continue
+			}
+			*count++
+			stats = append(stats, profile.RepoStats{
+				Name:     r.Name,
+				Language: r.Language,
+				Topics:   r.Topics,
+				Fork:     r.Fork,
+				Stars:    r.StargazersCount,
+				PushedAt: r.PushedAt,
+			})
+		}
+
+		if len(repos) < githubPageSize {
+			break
+		}
 	}
 
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/readme", username, repos[0])
+	return stats, nil
+}
+
+// rankRepos ordena los repos por relevancia: estrellas (en escala
+// logarítmica, para que un repo viral no tape todo) más actividad reciente,
+// que pierde la mitad de su valor cada 6 meses.
+func rankRepos(repos []profile.RepoStats, now time.Time) {
+	relevance := func(r profile.RepoStats) float64 {
+		months := now.Sub(r.PushedAt).Hours() / (24 * 30)
+		return math.Log2(1+float64(r.Stars)) + 3*math.Pow(0.5, months/6)
+	}
+	sort.SliceStable(repos, func(i, j int) bool {
+		return relevance(repos[i]) > relevance(repos[j])
+	})
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// fetchLanguages obtiene los bytes de código por lenguaje de un repo.
+// Devuelve nil si falla: el lenguaje principal sigue disponible.
+func (g *GitHubCollector) fetchLanguages(ctx context.Context, username, repo string) map[string]int {
+	body, err := g.get(ctx, fmt.Sprintf("%s/repos/%s/%s/languages",
+		g.opts.APIURL, url.PathEscape(username), url.PathEscape(repo)), githubJSON)
 	if err != nil {
 		return nil
 	}
 
-	req.Header.Set("Accept", "application/vnd.github.v3.raw")
-	if g.token != "" {
-		req.Header.Set("Authorization", "Bearer "+g.token)
+	var languages map[string]int
+	if err := json.Unmarshal(body, &languages); err != nil {
+		return nil
 	}
 
-	resp, err := g.client.Do(req)
-	if err != nil {
-		return nil
+	return languages
+}
+
+// collectReadmes junta el README del perfil (el repo username/username) y
+// los de los MaxReadmes repos mejor rankeados, hasta ReadmeBudget bytes.
+// Cada uno va precedido del nombre del repo entre corchetes: un título
+// Markdown inflaría la cuenta de títulos del README.
+func (g *GitHubCollector) collectReadmes(ctx context.Context, username string, repos []profile.RepoStats) *string {
+	candidates := []string{username}
+	for _, r := range repos {
+		if len(candidates) > g.opts.MaxReadmes {
+			break
+		}
+		if !strings.EqualFold(r.Name, username) {
+			candidates = append(candidates, r.Name)
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	var b strings.Builder
+	for _, repo := range candidates {
+		remaining := g.opts.ReadmeBudget - b.Len()
+		if remaining <= 0 {
+			break
+		}
+
+		readme := g.fetchReadme(ctx, username, repo)
+		if readme == nil || strings.TrimSpace(*readme) == "" {
+			continue
+		}
+
+		section := fmt.Sprintf("[%s]\n\n%s\n\n", repo, strings.TrimSpace(*readme))
+		b.WriteString(truncateBytes(section, remaining))
+	}
+
+	if b.Len() == 0 {
 		return nil
 	}
+	text := strings.TrimSpace(b.String())
+	return &text
+}
 
-	body, err := io.ReadAll(resp.Body)
+// truncateBytes corta s a n bytes sin partir un carácter UTF-8.
+func truncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+func (g *GitHubCollector) fetchReadme(ctx context.Context, username, repo string) *string {
+	body, err := g.get(ctx, fmt.Sprintf("%s/repos/%s/%s/readme",
+		g.opts.APIURL, url.PathEscape(username), url.PathEscape(repo)), githubRaw)
 	if err != nil {
 		return nil
 	}
@@ -145,12 +368,131 @@ func (g *GitHubCollector) fetchReadme(username string, repos []string) *string {
 	return &text
 }
 
-// setHeaders configura headers comunes para requests a GitHub API.
-func (g *GitHubCollector) setHeaders(req *http.Request) {
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	if g.token != "" {
-		req.Header.Set("Authorization", "Bearer "+g.token)
+// get hace un GET a la API y devuelve el cuerpo de una respuesta 200.
+// Si hay una respuesta guardada con ETag la pide condicionalmente y un 304
+// devuelve la guardada. Un 404 es errNotFound.
+func (g *GitHubCollector) get(ctx context.Context, endpoint, accept string) ([]byte, error) {
+	key := accept + " " + endpoint
+
+	for attempt := 0; ; attempt++ {
+		// No gastar un pedido si ya sabemos que la cuota está agotada
+		if wait := g.exhaustedFor(time.Now()); wait > 0 {
+			if wait > g.opts.RateLimitMaxWait {
+				return nil, &RateLimitError{Source: "github", Reset: time.Now().Add(wait)}
+			}
+			if err := g.sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", accept)
+		if g.token != "" {
+			req.Header.Set("Authorization", "Bearer "+g.token)
+		}
+		cached, hasCached := g.etags.get(key)
+		if hasCached {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+
+		resp, err := g.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		g.trackRateLimit(resp.Header)
+
+		switch {
+		case resp.StatusCode == http.StatusNotModified && hasCached:
+			return cached.body, nil
+
+		case resp.StatusCode == http.StatusOK:
+			if etag := resp.Header.Get("ETag"); etag != "" {
+				g.etags.put(key, etag, body)
+			}
+			return body, nil
+
+		case resp.StatusCode == http.StatusNotFound:
+			return nil, errNotFound
+
+		case isRateLimited(resp.StatusCode, resp.Header, body):
+			wait := rateLimitWait(resp.Header, attempt, time.Now())
+			if attempt >= githubMaxRetries || wait > g.opts.RateLimitMaxWait {
+				return nil, &RateLimitError{Source: "github", Reset: time.Now().Add(wait)}
+			}
+			if err := g.sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, fmt.Errorf("github API returned status %d", resp.StatusCode)
+		}
+	}
+}
+
+// trackRateLimit guarda la cuota restante informada por la API.
+func (g *GitHubCollector) trackRateLimit(h http.Header) {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.remaining = remaining
+	g.reset = time.Unix(reset, 0)
+}
+
+// exhaustedFor devuelve cuánto falta para el reset si la cuota está
+// agotada, o 0 si se puede pedir.
+func (g *GitHubCollector) exhaustedFor(now time.Time) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.remaining != 0 || !now.Before(g.reset) {
+		return 0
+	}
+	return g.reset.Sub(now)
+}
+
+// isRateLimited distingue un rechazo por rate limit de otros 403: la cuota
+// primaria agotada, o el límite secundario (abuso), que trae Retry-After o
+// lo dice en el mensaje.
+func isRateLimited(status int, h http.Header, body []byte) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	if status != http.StatusForbidden {
+		return false
+	}
+	return h.Get("Retry-After") != "" ||
+		h.Get("X-RateLimit-Remaining") == "0" ||
+		strings.Contains(strings.ToLower(string(body)), "rate limit")
+}
+
+// rateLimitWait decide cuánto esperar antes de reintentar, según lo que
+// indique la API: Retry-After, el reset de la cuota, o backoff exponencial
+// desde 1s si no hay headers.
+func rateLimitWait(h http.Header, attempt int, now time.Time) time.Duration {
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if h.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return max(time.Unix(reset, 0).Sub(now), 0) + time.Second
+		}
 	}
+	return time.Second << attempt
 }
 
 // githubUser representa la respuesta de la API de GitHub para un usuario.
@@ -163,5 +505,11 @@ type githubUser struct {
 
 // githubRepo representa un repositorio en la respuesta de GitHub.
 type githubRepo struct {
-	Name string `json:"name"`
+	Name            string    `json:"name"`
+	Language        string    `json:"language"`
+	Topics          []string  `json:"topics"`
+	Fork            bool      `json:"fork"`
+	Archived        bool      `json:"archived"`
+	StargazersCount int       `json:"stargazers_count"`
+	PushedAt        time.Time `json:"pushed_at"`
 }
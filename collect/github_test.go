@@ -0,0 +1,76 @@
+package collect
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newGitHubStub levanta una API mínima con tres repos y cuenta los pedidos
+// de lenguajes.
+func newGitHubStub(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+	var mu sync.Mutex
+	languageCalls := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch path := r.URL.EscapedPath(); {
+		case path == "/users/al%20ice":
+			fmt.Fprint(w, `{"login":"al ice","bio":"dev"}`)
+		case path == "/users/al%20ice/repos":
+			fmt.Fprint(w, `[{"name":"a","language":"Go"},{"name":"b","language":"C"},{"name":"c","language":"Nix"},{"name":"d","language":"Java","fork":true}]`)
+		case strings.HasSuffix(path, "/languages"):
+			mu.Lock()
+			languageCalls++
+			mu.Unlock()
+			fmt.Fprint(w, `{"Go":100}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &languageCalls
+}
+
+func TestGitHubLanguageRepos(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		token string
+		repos int
+		want  int
+	}{
+		{"no token skips per-repo languages", "", 0, 0},
+		{"token uses the default", "secret", 0, 3},
+		{"explicit limit", "", 2, 2},
+		{"negative disables", "secret", -1, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, calls := newGitHubStub(t)
+			g := NewGitHubCollector(tc.token, GitHubOptions{APIURL: srv.URL, LanguageRepos: tc.repos})
+
+			// El username se escapa en la ruta: "al ice" llega como al%20ice
+			data, err := g.Collect(context.Background(), "al ice")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *calls != tc.want {
+				t.Errorf("language requests = %d, want %d", *calls, tc.want)
+			}
+			if len(data.Languages) == 0 {
+				t.Error("Languages is empty: the primary language should still be used")
+			}
+
+			// El fork queda registrado pero no aporta nombres ni lenguajes
+			if containsString(data.Repositories, "d") || containsString(data.Languages, "Java") {
+				t.Errorf("fork leaked into Repositories %v or Languages %v", data.Repositories, data.Languages)
+			}
+			if len(data.Repos) != 4 || !data.Repos[3].Fork {
+				t.Errorf("Repos = %+v, want the fork recorded with Fork set", data.Repos)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package collect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"distroanalyzer/profile"
+)
+
+// DefaultGitLabURL es la instancia pública de GitLab.
+const DefaultGitLabURL = "https://gitlab.com"
+
+const (
+	gitlabPageSize    = 100 // máximo que acepta la API
+	gitlabMaxProjects = 100 // proyectos que se recorren como máximo, paginando
+
+	// Los lenguajes cuestan un request por proyecto: solo se piden para
+	// los más activos.
+	gitlabLanguageProjects = 5
+)
+
+// GitLabCollector recolecta datos de perfiles públicos de GitLab,
+// tanto de gitlab.com como de instancias self-hosted.
+type GitLabCollector struct {
+	client  *http.Client
+	baseURL string // URL de la instancia, sin /api/v4
+	token   string // Token opcional (PRIVATE-TOKEN) para perfiles y rate limits
+}
+
+// NewGitLabCollector crea un collector para la instancia en baseURL.
+// Vacío = gitlab.com. El token es opcional.
+func NewGitLabCollector(baseURL, token string) *GitLabCollector {
+	if baseURL == "" {
+		baseURL = DefaultGitLabURL
+	}
+	return &GitLabCollector{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+	}
+}
+
+// NewGitLabInstanceCollector crea un collector sin token para una instancia
+// elegida por el usuario ("gitlab:alice@gitlab.example.com"). Solo conecta
+// a direcciones públicas.
+func NewGitLabInstanceCollector(baseURL string) *GitLabCollector {
+	g := NewGitLabCollector(baseURL, "")
+	g.client.Transport = newWebTransport(false)
+	return g
+}
+
+// Collect obtiene bio, website, proyectos, sus topics y lenguajes, y el
+// README del proyecto propio más relevante del usuario de GitLab.
+func (g *GitLabCollector) Collect(ctx context.Context, username string) (*profile.RawData, error) {
+	// La API busca por username y devuelve una lista
+	var matches []gitlabUser
+	err := g.get(ctx, "/users?username="+url.QueryEscape(username), &matches)
+	if err == nil && len(matches) == 0 {
+		err = errNotFound
+	}
+	if err == nil {
+		// El detalle del usuario incluye bio, website y location
+		err = g.get(ctx, fmt.Sprintf("/users/%d", matches[0].ID), &matches[0])
+	}
+	if errors.Is(err, errNotFound) {
+		return nil, fmt.Errorf("gitlab user %q: %w", username, ErrUserNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	user := matches[0]
+
+	projects, err := g.fetchProjects(ctx, user.ID)
+	if errors.Is(err, ErrRateLimited) {
+		return nil, err
+	}
+	if err != nil {
+		projects = []gitlabProject{}
+	}
+
+	// Los forks quedan en Repos, marcados, pero no aportan señales
+	var ownProjects []gitlabProject
+	repos := make([]profile.RepoStats, 0, len(projects))
+	for _, p := range projects {
+		if p.ForkedFrom == nil {
+			ownProjects = append(ownProjects, p)
+		}
+		repos = append(repos, profile.RepoStats{
+			Name:     p.Name,
+			Topics:   append(p.Topics, p.TagList...),
+			Fork:     p.ForkedFrom != nil,
+			Stars:    p.StarCount,
+			PushedAt: p.LastActivityAt,
+		})
+	}
+	own, forks := splitForks(repos)
+	for i := range own[:min(len(own), gitlabLanguageProjects)] {
+		own[i].Languages = g.fetchLanguages(ctx, ownProjects[i].ID)
+	}
+	names, topics := repoNamesAndTopics(own)
+
+	data := &profile.RawData{
+		Bio:          user.Bio,
+		Repositories: names,
+		Topics:       topics,
+		Repos:        append(own, forks...),
+		Website:      user.WebsiteURL,
+		Location:     user.Location,
+		Email:        user.PublicEmail,
+		ReadmeText:   g.fetchReadme(ctx, mostRelevant(ownProjects)),
+	}
+	data.Languages = repoLanguages(data)
+
+	// Cancelado a mitad de camino: el README vacío no sería real
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// fetchProjects pagina los proyectos del usuario,
+// ordenados por actividad, hasta gitlabMaxProjects.
+func (g *GitLabCollector) fetchProjects(ctx context.Context, userID int) ([]gitlabProject, error) {
+	var projects []gitlabProject
+	for page := "1"; page != "" && len(projects) < gitlabMaxProjects; {
+		var batch []gitlabProject
+		path := fmt.Sprintf("/users/%d/projects?order_by=last_activity_at&per_page=%d&page=%s",
+			userID, gitlabPageSize, url.QueryEscape(page))
+		header, err := g.getWithHeader(ctx, path, &batch)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, batch...)
+		// La API deja X-Next-Page vacío en la última página
+		page = header.Get("X-Next-Page")
+	}
+	return projects[:min(len(projects), gitlabMaxProjects)], nil
+}
+
+// fetchLanguages obtiene los lenguajes de un proyecto. La API da
+// porcentajes; se devuelven como proporciones sobre 10000. nil si falla.
+func (g *GitLabCollector) fetchLanguages(ctx context.Context, projectID int) map[string]int {
+	var shares map[string]float64
+	if err := g.get(ctx, fmt.Sprintf("/projects/%d/languages", projectID), &shares); err != nil {
+		return nil
+	}
+	languages := make(map[string]int, len(shares))
+	for lang, share := range shares {
+		languages[lang] = int(math.Round(share * 100))
+	}
+	return languages
+}
+
+// mostRelevant elige el proyecto con más estrellas; ante empate, el de
+// actividad más reciente (la API los devuelve en ese orden).
+func mostRelevant(projects []gitlabProject) *gitlabProject {
+	var best *gitlabProject
+	for i := range projects {
+		if best == nil || projects[i].StarCount > best.StarCount {
+			best = &projects[i]
+		}
+	}
+	return best
+}
+
+func (g *GitLabCollector) fetchReadme(ctx context.Context, project *gitlabProject) *string {
+	if project == nil || project.ReadmeURL == "" || project.DefaultBranch == "" {
+		return nil
+	}
+
+	// readme_url apunta a la vista web: .../-/blob/<branch>/<archivo>
+	marker := "/-/blob/" + project.DefaultBranch + "/"
+	i := strings.Index(project.ReadmeURL, marker)
+	if i < 0 {
+		return nil
+	}
+	file := project.ReadmeURL[i+len(marker):]
+
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%d/repository/files/%s/raw?ref=%s",
+		g.baseURL, project.ID, url.PathEscape(file), url.QueryEscape(project.DefaultBranch))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil
+	}
+	g.setHeaders(req)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil
+	}
+
+	text := string(body)
+	return &text
+}
+
+// get hace un GET a la API v4 y decodifica la respuesta JSON en v.
+func (g *GitLabCollector) get(ctx context.Context, path string, v interface{}) error {
+	_, err := g.getWithHeader(ctx, path, v)
+	return err
+}
+
+// getWithHeader es get, pero devuelve también los headers de la respuesta
+// (la paginación viaja en X-Next-Page).
+func (g *GitLabCollector) getWithHeader(ctx context.Context, path string, v interface{}) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", g.baseURL+"/api/v4"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	g.setHeaders(req)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errNotFound
+	case http.StatusTooManyRequests:
+		return nil, &RateLimitError{Source: "gitlab", Reset: time.Now().Add(rateLimitWait(resp.Header, 0, time.Now()))}
+	default:
+		return nil, fmt.Errorf("gitlab API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, fmt.Errorf("invalid gitlab API response: %w", err)
+	}
+	return resp.Header, nil
+}
+
+// setHeaders configura headers comunes para requests a la API de GitLab.
+func (g *GitLabCollector) setHeaders(req *http.Request) {
+	req.Header.Set("Accept", "application/json")
+	if g.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", g.token)
+	}
+}
+
+// containsString verifica si un slice contiene un string.
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// gitlabUser representa la respuesta de la API de GitLab para un usuario.
+type gitlabUser struct {
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	Bio         string `json:"bio"`
+	WebsiteURL  string `json:"website_url"`
+	Location    string `json:"location"`
+	PublicEmail string `json:"public_email"`
+}
+
+// gitlabProject representa un proyecto en la respuesta de GitLab.
+type gitlabProject struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	Topics        []string `json:"topics"`
+	TagList       []string `json:"tag_list"` // nombre anterior de topics
+	StarCount     int      `json:"star_count"`
+	DefaultBranch string   `json:"default_branch"`
+	ReadmeURL     string   `json:"readme_url"`
+
+	LastActivityAt time.Time `json:"last_activity_at"`
+
+	// ForkedFrom solo viene en los forks
+	ForkedFrom *struct {
+		ID int `json:"id"`
+	} `json:"forked_from_project"`
+}
@@ -0,0 +1,116 @@
+package collect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newGitLabStub levanta una API v4 mínima con el usuario "alice" (id 7),
+// dos páginas de proyectos, sus lenguajes y un README.
+func newGitLabStub(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/users", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("username") != "alice" {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprint(w, `[{"id":7,"username":"alice"}]`)
+	})
+	mux.HandleFunc("/api/v4/users/7", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":7,"username":"alice","bio":"Kernel hacker","website_url":"https://alice.dev","location":"Rosario"}`)
+	})
+	mux.HandleFunc("/api/v4/users/7/projects", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("X-Next-Page", "2")
+			fmt.Fprint(w, `[{"id":1,"name":"dotfiles","topics":["nixos"],"star_count":1}]`)
+		case "2":
+			w.Header().Set("X-Next-Page", "")
+			fmt.Fprint(w, `[{"id":2,"name":"kernel-patches","tag_list":["linux"],"star_count":9,
+				"default_branch":"main","readme_url":"https://gitlab.example/alice/kernel-patches/-/blob/main/README.md"},
+				{"id":3,"name":"linux","star_count":500,"forked_from_project":{"id":99},
+				"default_branch":"master","readme_url":"https://gitlab.example/alice/linux/-/blob/master/README"}]`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+	mux.HandleFunc("/api/v4/projects/1/languages", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"Nix":90.0,"Shell":10.0}`)
+	})
+	mux.HandleFunc("/api/v4/projects/2/languages", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"C":95.0,"Shell":5.0}`)
+	})
+	mux.HandleFunc("/api/v4/projects/2/repository/files/README.md/raw", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ref") != "main" {
+			t.Errorf("README ref = %q, want main", r.URL.Query().Get("ref"))
+		}
+		fmt.Fprint(w, "# kernel-patches")
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGitLabCollect(t *testing.T) {
+	srv := newGitLabStub(t)
+	data, err := NewGitLabCollector(srv.URL, "").Collect(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data.Bio != "Kernel hacker" || data.Website != "https://alice.dev" || data.Location != "Rosario" {
+		t.Errorf("user fields = %q, %q, %q", data.Bio, data.Website, data.Location)
+	}
+	if got := fmt.Sprint(data.Repositories); got != "[dotfiles kernel-patches]" {
+		t.Errorf("Repositories = %s, want both pages without the fork", got)
+	}
+	if len(data.Repos) != 3 || !data.Repos[2].Fork || data.Repos[2].Name != "linux" {
+		t.Errorf("Repos = %+v, want the fork recorded last with Fork set", data.Repos)
+	}
+	if got := fmt.Sprint(data.Topics); got != "[nixos linux]" {
+		t.Errorf("Topics = %s", got)
+	}
+	if got := fmt.Sprint(data.Languages); got != "[C Nix Shell]" {
+		t.Errorf("Languages = %s, want ordered by summed share", got)
+	}
+	if data.ReadmeText == nil || *data.ReadmeText != "# kernel-patches" {
+		t.Errorf("ReadmeText = %v, want the most starred project's README", data.ReadmeText)
+	}
+}
+
+func TestGitLabCollectUserNotFound(t *testing.T) {
+	srv := newGitLabStub(t)
+	_, err := NewGitLabCollector(srv.URL, "").Collect(context.Background(), "bob")
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("err = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestGitLabCollect404IsUserNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := NewGitLabCollector(srv.URL, "").Collect(context.Background(), "alice")
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("err = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestGitLabCollectRateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	_, err := NewGitLabCollector(srv.URL, "").Collect(context.Background(), "alice")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("err = %v, want ErrRateLimited", err)
+	}
+}
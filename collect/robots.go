@@ -0,0 +1,272 @@
+package collect
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// robotsMaxBytes es cuánto de robots.txt se lee; RFC 9309 pide al
+	// menos 500 KiB y permite ignorar el resto.
+	robotsMaxBytes = 500 * 1024
+
+	// robotsErrorTTL es cuánto se recuerda un robots.txt que respondió 5xx:
+	// mientras tanto el host se trata como prohibido, pero es temporal.
+	robotsErrorTTL = time.Minute
+
+	// robotsMaxHosts acota la cantidad de hosts en caché.
+	robotsMaxHosts = 1024
+)
+
+// robotsRule es una línea Allow o Disallow del grupo que nos aplica.
+type robotsRule struct {
+	allow bool
+	path  string
+}
+
+// robotsRules son las reglas de un host para nuestro User-Agent. nil
+// permite todo.
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+	disallowed bool // el host no respondió: todo prohibido (RFC 9309)
+}
+
+// allowed aplica la regla más específica (la de patrón más largo) que
+// coincida con path; ante empate gana Allow. Sin reglas, se permite.
+func (r *robotsRules) allowed(path string) bool {
+	if r == nil {
+		return true
+	}
+	if r.disallowed {
+		return false
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+
+	allow, best := true, -1
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.path, path) {
+			continue
+		}
+		if len(rule.path) > best || (len(rule.path) == best && rule.allow) {
+			allow, best = rule.allow, len(rule.path)
+		}
+	}
+	return allow
+}
+
+// robotsMatch compara un patrón de robots.txt con una ruta: "*" es
+// cualquier secuencia y un "$" final ancla al final de la ruta.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		// El último segmento de un patrón anclado va al final de la ruta
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	return !anchored || rest == ""
+}
+
+// parseRobots extrae de un robots.txt las reglas para agent, el product
+// token del User-Agent ("DistroAnalyzer"). Si ningún grupo lo nombra se
+// usan los de "*". Los grupos que coinciden se combinan.
+func parseRobots(r io.Reader, agent string) *robotsRules {
+	agent = strings.ToLower(agent)
+
+	var (
+		own, star         robotsRules
+		ownFound          bool
+		current           []*robotsRules // grupos a los que aplica la línea actual
+		inAgents, matched bool
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			// Un User-agent después de reglas abre un grupo nuevo
+			if !inAgents {
+				current, matched = nil, false
+			}
+			inAgents = true
+
+			name, _, _ := strings.Cut(strings.ToLower(value), "/")
+			switch {
+			case name == agent:
+				current = append(current, &own)
+				ownFound, matched = true, true
+			case name == "*":
+				current = append(current, &star)
+			}
+			continue
+		}
+		inAgents = false
+
+		// "*" solo aplica si el grupo no nos nombra también
+		targets := current
+		if matched {
+			targets = []*robotsRules{&own}
+		}
+		for _, group := range targets {
+			switch key {
+			case "allow", "disallow":
+				// "Disallow:" vacío no prohíbe nada
+				if value != "" {
+					group.rules = append(group.rules, robotsRule{allow: key == "allow", path: value})
+				}
+			case "crawl-delay":
+				if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+					group.crawlDelay = time.Duration(secs * float64(time.Second))
+				}
+			}
+		}
+	}
+
+	if ownFound {
+		return &own
+	}
+	return &star
+}
+
+// robotsEntry es lo que se recuerda de cada host.
+type robotsEntry struct {
+	rules   *robotsRules
+	expires time.Time
+	next    time.Time // primer momento en que se puede volver a pedir
+}
+
+// robotsCache guarda los robots.txt por host ("https://example.com") y
+// reparte los turnos de pedidos que impone su Crawl-delay.
+type robotsCache struct {
+	mu      sync.Mutex
+	entries map[string]*robotsEntry
+}
+
+func newRobotsCache() *robotsCache {
+	return &robotsCache{entries: make(map[string]*robotsEntry)}
+}
+
+// get devuelve las reglas vigentes de host, si están en caché.
+func (c *robotsCache) get(host string, now time.Time) (*robotsRules, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[host]
+	if !ok || now.After(entry.expires) {
+		return nil, false
+	}
+	return entry.rules, true
+}
+
+// put guarda las reglas de host, conservando su turno de pedidos.
+func (c *robotsCache) put(host string, rules *robotsRules, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[host]; ok {
+		entry.rules, entry.expires = rules, expires
+		return
+	}
+	if len(c.entries) >= robotsMaxHosts {
+		c.evict(time.Now())
+	}
+	c.entries[host] = &robotsEntry{rules: rules, expires: expires}
+}
+
+// evict descarta los hosts vencidos y, si no alcanza, uno cualquiera.
+func (c *robotsCache) evict(now time.Time) {
+	for host, entry := range c.entries {
+		if now.After(entry.expires) && now.After(entry.next) {
+			delete(c.entries, host)
+		}
+	}
+	for host := range c.entries {
+		if len(c.entries) < robotsMaxHosts {
+			break
+		}
+		delete(c.entries, host)
+	}
+}
+
+// reserve aparta el próximo turno para pedir a host respetando su
+// Crawl-delay y devuelve cuánto hay que esperar. Si la espera supera
+// maxWait no aparta nada y devuelve ok=false.
+func (c *robotsCache) reserve(host string, delay, maxWait time.Duration, now time.Time) (wait time.Duration, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, found := c.entries[host]
+	if !found {
+		return 0, true
+	}
+
+	start := now
+	if entry.next.After(now) {
+		start = entry.next
+	}
+	wait = start.Sub(now)
+	if wait > maxWait {
+		return wait, false
+	}
+	entry.next = start.Add(delay)
+	return wait, true
+}
+
+// robotsFetchKey marca en el contexto los requests que bajan un
+// robots.txt, para que checkRedirect siga sus redirects sin chequeo.
+type robotsFetchKey struct{}
+
+// fetchRobots pide el robots.txt de base ("https://example.com").
+// Un 4xx (o cualquier otro estado) significa que no hay restricciones; un
+// 5xx, que el host está prohibido por ahora.
+func (w *WebCollector) fetchRobots(ctx context.Context, base string) (*robotsRules, time.Duration, error) {
+	ctx = context.WithValue(ctx, robotsFetchKey{}, true)
+	req, err := http.NewRequestWithContext(ctx, "GET", base+"/robots.txt", nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("User-Agent", w.opts.UserAgent)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return &robotsRules{disallowed: true}, robotsErrorTTL, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, w.opts.RobotsTTL, nil
+	}
+
+	return parseRobots(io.LimitReader(resp.Body, robotsMaxBytes), w.agent), w.opts.RobotsTTL, nil
+}
@@ -0,0 +1,103 @@
+package collect
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"distroanalyzer/profile"
+)
+
+// SourceWeb es la fuente de las URLs sin prefijo.
+const SourceWeb = "web"
+
+// Target es el resultado de resolver una entrada: qué collector usar y con
+// qué identificador.
+type Target struct {
+	// Source es la fuente resuelta, calificada con el host para instancias
+	// self-hosted ("github", "gitlab@gitlab.example.com").
+	Source string
+
+	// ID es lo que recibe el collector: username o URL.
+	ID string
+
+	Collector Collector
+}
+
+// Resolve elige la fuente según la sintaxis de la entrada:
+//
+//	alice                           → fallback (o la fuente default)
+//	github:alice, codeberg:alice    → la fuente del prefijo
+//	gitlab:alice@gitlab.example.com → instancia self-hosted de la fuente
+//	https://alice.dev               → web
+func (r *Registry) Resolve(input, fallback string) (*Target, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, &InvalidInputError{Input: input, Reason: "empty input"}
+	}
+
+	if strings.Contains(input, "://") {
+		return r.target(SourceWeb, input)
+	}
+
+	source, id := fallback, input
+	if name, rest, ok := strings.Cut(input, ":"); ok {
+		source, id = strings.ToLower(name), strings.TrimSpace(rest)
+	}
+	if source == "" {
+		source = r.Default()
+	}
+	if id == "" {
+		return nil, &InvalidInputError{Input: input, Reason: "missing username"}
+	}
+	if source == SourceWeb {
+		return r.target(source, id)
+	}
+
+	if user, host, ok := strings.Cut(id, "@"); ok {
+		return r.instance(input, source, user, host)
+	}
+	return r.target(source, id)
+}
+
+// Collect resuelve la entrada y recolecta con el collector elegido.
+func (r *Registry) Collect(ctx context.Context, input string) (*profile.RawData, error) {
+	target, err := r.Resolve(input, "")
+	if err != nil {
+		return nil, err
+	}
+	return target.Collector.Collect(ctx, target.ID)
+}
+
+func (r *Registry) target(source, id string) (*Target, error) {
+	c, err := r.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	return &Target{Source: source, ID: id, Collector: c}, nil
+}
+
+// instance arma el target de una instancia self-hosted. El collector se crea
+// en cada pedido y sin token: las credenciales configuradas son solo para
+// la instancia default de cada fuente. El host tiene que ser público.
+func (r *Registry) instance(input, source, user, host string) (*Target, error) {
+	factory, ok := r.factories[source]
+	if !ok {
+		return nil, &InvalidInputError{Input: input, Reason: "source " + source + " does not support self-hosted instances"}
+	}
+
+	host = strings.ToLower(host)
+	if u, err := url.Parse("https://" + host); user == "" || err != nil || u.Host != host || u.User != nil {
+		return nil, &InvalidInputError{Input: input, Reason: "expected user@host"}
+	}
+	// Las instancias las elige cualquiera: nada de apuntar a la red interna
+	if u, _ := url.Parse("https://" + host); !isPublicHost(u.Hostname()) {
+		return nil, &InvalidInputError{Input: input, Reason: "host is not a public address"}
+	}
+
+	return &Target{
+		Source:    source + "@" + host,
+		ID:        user,
+		Collector: factory("https://" + host),
+	}, nil
+}
@@ -0,0 +1,89 @@
+package collect
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// blockedPrefixes son rangos que no son de Internet y que netip no
+// clasifica: CGNAT, "esta red", benchmarks y reservados.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// isPublicAddr indica si ip es una dirección de Internet: no loopback,
+// privada, link-local, multicast ni reservada.
+func isPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPublicHost descarta de entrada hosts que no son de Internet: localhost
+// y direcciones IP literales no públicas. Un nombre que resuelve a una IP
+// privada lo frena recién publicOnly al conectar.
+func isPublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return isPublicAddr(ip)
+	}
+	return true
+}
+
+// publicOnly es el Control de un net.Dialer que rechaza conexiones a
+// direcciones no públicas. Corre después de resolver el DNS, así que
+// también cubre redirects y DNS rebinding.
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddr(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, ip)
+	}
+	return nil
+}
+
+// newWebTransport arma el transporte del WebCollector. Salvo allowPrivate,
+// solo conecta a direcciones públicas y no usa el proxy del entorno: con
+// proxy el chequeo vería la IP del proxy y no la del destino.
+func newWebTransport(allowPrivate bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if allowPrivate {
+		return transport
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicOnly,
+	}
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+	return transport
+}
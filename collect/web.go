@@ -1,9 +1,12 @@
 package collect
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -11,36 +14,105 @@ import (
 	"golang.org/x/net/html"
 )
 
-// WebCollector recolecta datos de URLs web públicas.
+// WebOptions ajusta cómo WebCollector visita sitios. Los campos en cero
+// usan los valores por defecto.
+type WebOptions struct {
+	// UserAgent se envía en cada pedido; su product token ("DistroAnalyzer")
+	// es el que se busca en robots.txt.
+	UserAgent string
+
+	// MaxBodyBytes es cuánto de cada página se lee como máximo.
+	MaxBodyBytes int
+
+	// MaxCrawlDelay es cuánto se espera como máximo para respetar el
+	// Crawl-delay de un sitio antes de fallar con *RateLimitError.
+	MaxCrawlDelay time.Duration
+
+	// RobotsTTL es cuánto se recuerda el robots.txt de cada host.
+	RobotsTTL time.Duration
+
+	// AllowPrivate permite visitar direcciones privadas y loopback. Solo
+	// para desarrollo: abre la puerta a SSRF contra la red interna.
+	AllowPrivate bool
+}
+
+// Valores por defecto de WebOptions.
+const (
+	DefaultWebUserAgent     = "DistroAnalyzer/1.0"
+	DefaultWebMaxBodyBytes  = 2 * 1024 * 1024
+	DefaultWebMaxCrawlDelay = 10 * time.Second
+	DefaultWebRobotsTTL     = time.Hour
+)
+
+// webMaxText es cuánto texto visible se conserva de la página.
+const webMaxText = 5000
+
+// webMaxRedirects es el mismo límite de redirects que el de net/http.
+const webMaxRedirects = 10
+
+// WebCollector recolecta datos de URLs web públicas respetando robots.txt
+// y el Crawl-delay de cada sitio.
 type WebCollector struct {
 	client *http.Client
+	opts   WebOptions
+	agent  string // product token del User-Agent, para robots.txt
+	robots *robotsCache
+	sleep  func(context.Context, time.Duration) error
 }
 
 // NewWebCollector crea un nuevo collector para páginas web.
-func NewWebCollector() *WebCollector {
-	return &WebCollector{
+func NewWebCollector(opts WebOptions) *WebCollector {
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultWebUserAgent
+	}
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultWebMaxBodyBytes
+	}
+	if opts.MaxCrawlDelay == 0 {
+		opts.MaxCrawlDelay = DefaultWebMaxCrawlDelay
+	} else if opts.MaxCrawlDelay < 0 {
+		opts.MaxCrawlDelay = 0
+	}
+	if opts.RobotsTTL <= 0 {
+		opts.RobotsTTL = DefaultWebRobotsTTL
+	}
+
+	token, _, _ := strings.Cut(strings.TrimSpace(opts.UserAgent), " ")
+	agent, _, _ := strings.Cut(token, "/")
+	w := &WebCollector{
 		client: &http.Client{
-			Timeout: 15 * time.Second,
+			Timeout:   15 * time.Second,
+			Transport: newWebTransport(opts.AllowPrivate),
 		},
+		opts:   opts,
+		agent:  agent,
+		robots: newRobotsCache(),
+		sleep:  sleepContext,
 	}
+	w.client.CheckRedirect = w.checkRedirect
+	return w
 }
 
-// Collect obtiene contenido de una URL y extrae información básica.
-func (w *WebCollector) Collect(url string) (*profile.RawData, error) {
-	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
-		url = "https://" + url
+// Collect obtiene una página y extrae sus metadatos estructurados (bio,
+// ubicación, perfiles enlazados) y el texto visible.
+func (w *WebCollector) Collect(ctx context.Context, rawURL string) (*profile.RawData, error) {
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		rawURL = "https://" + rawURL
+	}
+	target, err := url.Parse(rawURL)
+	if err != nil || target.Host == "" {
+		return nil, &InvalidInputError{Input: rawURL, Reason: "invalid URL"}
 	}
 
-	// Verificar robots.txt (simplificado)
-	if !w.canFetch(url) {
-		return nil, fmt.Errorf("disallowed by robots.txt")
+	if err := w.waitTurn(ctx, target); err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", target.String(), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", "DistroAnalyzer/1.0")
+	req.Header.Set("User-Agent", w.opts.UserAgent)
 
 	resp, err := w.client.Do(req)
 	if err != nil {
@@ -52,34 +124,104 @@ func (w *WebCollector) Collect(url string) (*profile.RawData, error) {
 		return nil, fmt.Errorf("HTTP status %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	// Lo que pase del límite se descarta: el HTML parcial se parsea igual
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(w.opts.MaxBodyBytes)))
 	if err != nil {
 		return nil, err
 	}
 
-	text := w.extractText(string(body))
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("parse HTML: %w", err)
+	}
+
+	// Los enlaces relativos se resuelven respecto de la URL final
+	meta := extractMetadata(doc, resp.Request.URL)
+	text := w.extractText(doc)
 
 	return &profile.RawData{
-		Bio:        "", // Web no tiene bio estructurada
-		Website:    url,
-		ReadmeText: &text,
+		Bio:            meta.Description,
+		Repositories:   meta.Repos,
+		Website:        target.String(),
+		Location:       meta.Location,
+		Email:          meta.Email,
+		Links:          meta.Links,
+		LinkedProfiles: meta.Profiles,
+		ReadmeText:     &text,
 	}, nil
 }
 
-// canFetch verifica si se puede scrapear la URL (robots.txt básico).
-func (w *WebCollector) canFetch(url string) bool {
-	// Implementación simplificada: siempre permite
-	// En producción: parsear robots.txt del dominio
-	return true
-}
+// waitTurn consulta el robots.txt del host de target y espera el turno que
+// impone su Crawl-delay. Falla con ErrRobotsDisallowed si la ruta está
+// prohibida, o con *RateLimitError si el turno está más lejos que
+// MaxCrawlDelay.
+func (w *WebCollector) waitTurn(ctx context.Context, target *url.URL) error {
+	base := target.Scheme + "://" + target.Host
 
-// extractText extrae texto visible de HTML.
-func (w *WebCollector) extractText(htmlContent string) string {
-	doc, err := html.Parse(strings.NewReader(htmlContent))
+	rules, err := w.robotsFor(ctx, target)
 	if err != nil {
-		return ""
+		return err
+	}
+
+	var delay time.Duration
+	if rules != nil {
+		delay = rules.crawlDelay
 	}
+	now := time.Now()
+	wait, ok := w.robots.reserve(base, delay, w.opts.MaxCrawlDelay, now)
+	if !ok {
+		return &RateLimitError{Source: target.Host, Reset: now.Add(wait)}
+	}
+	if wait > 0 {
+		return w.sleep(ctx, wait)
+	}
+	return nil
+}
 
+// robotsFor devuelve las reglas del host de target, pidiendo su robots.txt
+// si no está en caché, y falla con ErrRobotsDisallowed si la ruta está
+// prohibida.
+func (w *WebCollector) robotsFor(ctx context.Context, target *url.URL) (*robotsRules, error) {
+	base := target.Scheme + "://" + target.Host
+
+	rules, ok := w.robots.get(base, time.Now())
+	if !ok {
+		fetched, ttl, err := w.fetchRobots(ctx, base)
+		if err != nil {
+			return nil, fmt.Errorf("fetch robots.txt: %w", err)
+		}
+		w.robots.put(base, fetched, time.Now().Add(ttl))
+		rules = fetched
+	}
+
+	if !rules.allowed(target.RequestURI()) {
+		return nil, fmt.Errorf("%s: %w", target, ErrRobotsDisallowed)
+	}
+	return rules, nil
+}
+
+// checkRedirect aplica robots.txt a cada redirect: si cambia de host,
+// también su Crawl-delay. Los redirects al bajar el propio robots.txt se
+// siguen sin chequeo, como pide RFC 9309; se reconocen por el contexto y no
+// por la ruta, porque el usuario también puede pedir /robots.txt.
+func (w *WebCollector) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= webMaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", webMaxRedirects)
+	}
+	if fetching, _ := req.Context().Value(robotsFetchKey{}).(bool); fetching {
+		return nil
+	}
+
+	prev := via[len(via)-1].URL
+	if req.URL.Scheme != prev.Scheme || req.URL.Host != prev.Host {
+		return w.waitTurn(req.Context(), req.URL)
+	}
+	_, err := w.robotsFor(req.Context(), req.URL)
+	return err
+}
+
+// extractText extrae texto visible de HTML.
+func (w *WebCollector) extractText(doc *html.Node) string {
 	var text strings.Builder
 	var traverse func(*html.Node)
 
@@ -106,10 +248,5 @@ func (w *WebCollector) extractText(htmlContent string) string {
 
 	traverse(doc)
 
-	result := text.String()
-	if len(result) > 5000 {
-		result = result[:5000] // Limitar texto extraído
-	}
-
-	return result
+	return truncateBytes(text.String(), webMaxText) // Limitar texto extraído
 }
@@ -0,0 +1,82 @@
+package collect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newRobotsSite levanta un sitio cuyo robots.txt prohíbe /private y que
+// redirige /go?to=URL a URL.
+func newRobotsSite(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+		case "/go":
+			http.Redirect(w, r, r.URL.Query().Get("to"), http.StatusFound)
+		default:
+			fmt.Fprint(w, `<html><head><meta name="description" content="hola"></head></html>`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// Un /robots.txt pedido por el usuario no es el fetch interno: sus
+// redirects se chequean igual.
+func TestWebCollectUserRobotsRedirectIsChecked(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			http.Redirect(w, r, "/private/robots.txt", http.StatusFound)
+		case "/private/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	w := NewWebCollector(WebOptions{AllowPrivate: true})
+	_, err := w.Collect(context.Background(), srv.URL+"/robots.txt")
+	if !errors.Is(err, ErrRobotsDisallowed) {
+		t.Errorf("err = %v, want ErrRobotsDisallowed", err)
+	}
+}
+
+func TestWebCollectRedirectRespectsRobots(t *testing.T) {
+	origin, other := newRobotsSite(t), newRobotsSite(t)
+
+	for _, tc := range []struct {
+		name    string
+		to      string
+		blocked bool
+	}{
+		{"same host, disallowed path", origin.URL + "/private", true},
+		{"new host, disallowed path", other.URL + "/private", true},
+		{"new host, allowed path", other.URL + "/about", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := NewWebCollector(WebOptions{AllowPrivate: true})
+			data, err := w.Collect(context.Background(), origin.URL+"/go?to="+tc.to)
+
+			if tc.blocked {
+				if !errors.Is(err, ErrRobotsDisallowed) {
+					t.Errorf("err = %v, want ErrRobotsDisallowed", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data.Bio != "hola" {
+				t.Errorf("Bio = %q", data.Bio)
+			}
+		})
+	}
+}
@@ -0,0 +1,405 @@
+package collect
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// webMetadata es lo que se extrae de los metadatos de una página personal:
+// OpenGraph, meta description, JSON-LD, rel="me" y h-card.
+type webMetadata struct {
+	Description string
+	Location    string
+	Email       string
+
+	// Links son los perfiles que la página declara como propios y los
+	// repos de forjas enlazados que no son de esos perfiles.
+	Links []string
+
+	// Repos son los repositorios de forjas enlazados ("dotfiles") cuyo
+	// dueño es un perfil que la página declara como propio.
+	Repos []string
+
+	// Profiles son los perfiles de forjas que la página declara como
+	// propios, como entrada del Registry ("github:octocat").
+	Profiles []string
+
+	// owners son los perfiles de forjas declarados como propios (rel="me",
+	// sameAs, u-url) y repos los enlaces a repos, que se asignan al final.
+	owners []string
+	repos  []forgeRepo
+}
+
+// forgeRepo es un enlace a un repo de forja.
+type forgeRepo struct {
+	owner string // "github:octocat", en minúsculas
+	name  string
+	url   string
+}
+
+// forgeHosts asocia los hosts de forjas conocidas con su fuente.
+var forgeHosts = map[string]string{
+	"github.com":   "github",
+	"gitlab.com":   "gitlab",
+	"codeberg.org": "codeberg",
+}
+
+// forgeReserved son rutas de primer nivel de las forjas que no son usuarios.
+var forgeReserved = []string{
+	"-", "about", "collections", "explore", "features", "login", "marketplace",
+	"orgs", "pricing", "search", "settings", "signup", "sponsors", "topics",
+	"trending", "user", "users",
+}
+
+// forgeName valida un usuario o repo de forja.
+var forgeName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// extractMetadata recorre doc, la página en base, y arma sus metadatos.
+// Para la descripción gana el dato más estructurado: JSON-LD, h-card,
+// OpenGraph y por último la meta description.
+func extractMetadata(doc *html.Node, base *url.URL) *webMetadata {
+	meta := &webMetadata{}
+	tags := make(map[string]string)
+	var person, card *webMetadata
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "meta":
+				key := strings.ToLower(attr(n, "property"))
+				if key == "" {
+					key = strings.ToLower(attr(n, "name"))
+				}
+				if _, seen := tags[key]; key != "" && !seen {
+					tags[key] = strings.TrimSpace(attr(n, "content"))
+				}
+			case "script":
+				if strings.EqualFold(attr(n, "type"), "application/ld+json") && person == nil {
+					person = parseJSONLD(textContent(n))
+				}
+				return
+			case "style":
+				return
+			}
+
+			if hasToken(attr(n, "rel"), "me") {
+				meta.addLink(base, attr(n, "href"))
+			}
+			if n.Data == "a" || n.Data == "link" {
+				meta.addForgeLink(base, attr(n, "href"), false)
+			}
+			if card == nil && hasToken(attr(n, "class"), "h-card") {
+				card = parseHCard(n, base)
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(doc)
+
+	for _, found := range []*webMetadata{person, card} {
+		if found == nil {
+			continue
+		}
+		meta.Description = firstNonEmpty(meta.Description, found.Description)
+		meta.Location = firstNonEmpty(meta.Location, found.Location)
+		meta.Email = firstNonEmpty(meta.Email, found.Email)
+		for _, link := range found.Links {
+			meta.addLink(base, link)
+		}
+	}
+	meta.Description = firstNonEmpty(meta.Description,
+		tags["og:description"], tags["description"], tags["twitter:description"])
+	meta.assignRepos()
+
+	return meta
+}
+
+// assignRepos reparte los repos enlazados: los de perfiles propios son
+// repos del usuario; los de otros (un proyecto que recomienda, por
+// ejemplo) quedan solo como enlace.
+func (m *webMetadata) assignRepos() {
+	for _, repo := range m.repos {
+		switch {
+		case containsString(m.owners, repo.owner):
+			if !containsString(m.Repos, repo.name) {
+				m.Repos = append(m.Repos, repo.name)
+			}
+		case !containsString(m.Links, repo.url):
+			m.Links = append(m.Links, repo.url)
+		}
+	}
+}
+
+// addLink agrega un perfil propio y, si es de una forja, el perfil para
+// encadenar el análisis.
+func (m *webMetadata) addLink(base *url.URL, href string) {
+	link := resolveLink(base, href)
+	if link == nil || (link.Scheme != "http" && link.Scheme != "https") {
+		return
+	}
+	if !containsString(m.Links, link.String()) {
+		m.Links = append(m.Links, link.String())
+	}
+	m.addForgeLink(base, href, true)
+}
+
+// addForgeLink reconoce enlaces a forjas: "github.com/octocat" es un
+// perfil y "github.com/octocat/dotfiles" un repo. own indica que la página
+// declara el enlace como propio.
+func (m *webMetadata) addForgeLink(base *url.URL, href string, own bool) {
+	link := resolveLink(base, href)
+	if link == nil {
+		return
+	}
+	source, ok := forgeHosts[strings.TrimPrefix(strings.ToLower(link.Hostname()), "www.")]
+	if !ok {
+		return
+	}
+
+	segments := strings.Split(strings.Trim(link.Path, "/"), "/")
+	user := segments[0]
+	if !forgeName.MatchString(user) || containsString(forgeReserved, strings.ToLower(user)) {
+		return
+	}
+
+	owner := strings.ToLower(source + ":" + user)
+	if own && !containsString(m.owners, owner) {
+		m.owners = append(m.owners, owner)
+	}
+
+	if len(segments) == 1 {
+		// Un enlace cualquiera a un perfil no lo hace del usuario
+		if !own {
+			return
+		}
+		if input := source + ":" + user; !containsString(m.Profiles, input) {
+			m.Profiles = append(m.Profiles, input)
+		}
+		return
+	}
+	repo := strings.TrimSuffix(segments[1], ".git")
+	if forgeName.MatchString(repo) {
+		m.repos = append(m.repos, forgeRepo{owner: owner, name: repo, url: link.String()})
+	}
+}
+
+// parseJSONLD busca en un bloque JSON-LD la Person que describe la página,
+// directa, dentro de @graph o como mainEntity de una ProfilePage.
+func parseJSONLD(data string) *webMetadata {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		return nil
+	}
+	return findPerson(doc)
+}
+
+func findPerson(node interface{}) *webMetadata {
+	switch v := node.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if found := findPerson(item); found != nil {
+				return found
+			}
+		}
+	case map[string]interface{}:
+		if graph, ok := v["@graph"]; ok {
+			return findPerson(graph)
+		}
+		switch {
+		case hasType(v, "Person"):
+			return personMetadata(v)
+		case hasType(v, "ProfilePage"):
+			return findPerson(v["mainEntity"])
+		}
+	}
+	return nil
+}
+
+// personMetadata lee los campos de una Person de schema.org.
+func personMetadata(person map[string]interface{}) *webMetadata {
+	meta := &webMetadata{
+		Description: jsonString(person["description"]),
+		Email:       strings.TrimPrefix(jsonString(person["email"]), "mailto:"),
+		Location:    jsonPlace(person["address"]),
+	}
+	if meta.Location == "" {
+		meta.Location = jsonPlace(person["homeLocation"])
+	}
+
+	// sameAs puede ser un string o una lista
+	switch same := person["sameAs"].(type) {
+	case string:
+		meta.Links = append(meta.Links, same)
+	case []interface{}:
+		for _, link := range same {
+			if s := jsonString(link); s != "" {
+				meta.Links = append(meta.Links, s)
+			}
+		}
+	}
+	return meta
+}
+
+// hasType indica si un nodo JSON-LD tiene el @type dado (string o lista).
+func hasType(node map[string]interface{}, typ string) bool {
+	switch t := node["@type"].(type) {
+	case string:
+		return t == typ
+	case []interface{}:
+		for _, item := range t {
+			if item == typ {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonPlace lee un lugar de schema.org: texto, PostalAddress o Place.
+func jsonPlace(v interface{}) string {
+	switch place := v.(type) {
+	case string:
+		return strings.TrimSpace(place)
+	case map[string]interface{}:
+		if name := jsonString(place["name"]); name != "" {
+			return name
+		}
+		if address, ok := place["address"]; ok {
+			return jsonPlace(address)
+		}
+		var parts []string
+		for _, key := range []string{"addressLocality", "addressRegion", "addressCountry"} {
+			if part := jsonPlace(place[key]); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+func jsonString(v interface{}) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(s)
+}
+
+// parseHCard lee las propiedades de un microformato h-card.
+func parseHCard(card *html.Node, base *url.URL) *webMetadata {
+	meta := &webMetadata{}
+	var locality, region, country string
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if n.Type != html.ElementNode {
+			return
+		}
+		// Una h-card anidada (p. ej. una organización) no es de la persona
+		if n != card && hasToken(attr(n, "class"), "h-card") {
+			return
+		}
+
+		class := attr(n, "class")
+		switch {
+		case hasToken(class, "p-note"):
+			meta.Description = firstNonEmpty(meta.Description, textContent(n))
+		case hasToken(class, "p-locality"):
+			locality = textContent(n)
+		case hasToken(class, "p-region"):
+			region = textContent(n)
+		case hasToken(class, "p-country-name"):
+			country = textContent(n)
+		case hasToken(class, "p-adr"), hasToken(class, "p-location"):
+			meta.Location = firstNonEmpty(meta.Location, textContent(n))
+		}
+		if hasToken(class, "u-email") {
+			email := strings.TrimPrefix(firstNonEmpty(attr(n, "href"), textContent(n)), "mailto:")
+			meta.Email = firstNonEmpty(meta.Email, email)
+		}
+		if hasToken(class, "u-url") && attr(n, "href") != "" {
+			meta.Links = append(meta.Links, attr(n, "href"))
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(card)
+
+	if meta.Location == "" {
+		var parts []string
+		for _, part := range []string{locality, region, country} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		meta.Location = strings.Join(parts, ", ")
+	}
+	return meta
+}
+
+// resolveLink resuelve href respecto de la página. nil si es inválido.
+func resolveLink(base *url.URL, href string) *url.URL {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return nil
+	}
+	link, err := url.Parse(href)
+	if err != nil {
+		return nil
+	}
+	return base.ResolveReference(link)
+}
+
+// attr devuelve el valor de un atributo del nodo, o "".
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// hasToken indica si una lista separada por espacios (class, rel)
+// contiene token.
+func hasToken(list, token string) bool {
+	for _, field := range strings.Fields(list) {
+		if strings.EqualFold(field, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// textContent concatena el texto de un nodo y sus hijos.
+func textContent(n *html.Node) string {
+	var text strings.Builder
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			text.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(n)
+	return strings.Join(strings.Fields(text.String()), " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
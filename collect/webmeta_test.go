@@ -0,0 +1,45 @@
+package collect
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestExtractMetadataForgeRepos(t *testing.T) {
+	page := `<html><head>
+<link rel="me" href="https://github.com/Alice">
+<script type="application/ld+json">
+{"@type": "Person", "sameAs": ["https://codeberg.org/alice"]}
+</script>
+</head><body>
+<a href="https://github.com/alice/dotfiles">mis dotfiles</a>
+<a href="https://codeberg.org/alice/nvim-config.git">nvim</a>
+<a href="https://github.com/torvalds/linux">el kernel</a>
+<a href="https://gitlab.com/bob">un amigo</a>
+</body></html>`
+
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, _ := url.Parse("https://alice.dev/")
+	meta := extractMetadata(doc, base)
+
+	// Solo son repos del usuario los de perfiles que la página declara
+	if got := fmt.Sprint(meta.Repos); got != "[dotfiles nvim-config]" {
+		t.Errorf("Repos = %s", got)
+	}
+	if !containsString(meta.Links, "https://github.com/torvalds/linux") {
+		t.Errorf("Links = %v, want the foreign repo URL", meta.Links)
+	}
+	if containsString(meta.Repos, "linux") {
+		t.Error("a repo owned by someone else was attributed to the user")
+	}
+	if got := fmt.Sprint(meta.Profiles); got != "[github:Alice codeberg:alice]" {
+		t.Errorf("Profiles = %s, want only the declared ones", got)
+	}
+}
@@ -0,0 +1,97 @@
+package httpapi
+
+import (
+	"context"
+	"sync"
+
+	"distroanalyzer/profile"
+)
+
+// flightGroup junta los análisis concurrentes de una misma clave: el
+// primero (líder) corre el pipeline y los que llegan mientras tanto
+// esperan su resultado.
+//
+// El pipeline no usa el contexto de ningún request en particular: sigue
+// mientras quede alguien esperando y se cancela cuando se van todos.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+type flight struct {
+	done    chan struct{}
+	prof    *profile.Profile
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{calls: make(map[string]*flight)}
+}
+
+// Do corre fn para key, o espera la ejecución que ya está en curso.
+// shared indica si el resultado vino de otro request.
+func (g *flightGroup) Do(ctx context.Context, key string, fn func(context.Context) (*profile.Profile, error)) (prof *profile.Profile, shared bool, err error) {
+	g.mu.Lock()
+	if f, ok := g.calls[key]; ok {
+		f.waiters++
+		g.mu.Unlock()
+		prof, err := g.wait(ctx, key, f)
+		return prof, true, err
+	}
+
+	flightCtx, cancel := detach(ctx)
+	f := &flight{done: make(chan struct{}), waiters: 1, cancel: cancel}
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	go func() {
+		f.prof, f.err = fn(flightCtx)
+		g.forget(key, f)
+		cancel()
+		close(f.done)
+	}()
+
+	prof, err = g.wait(ctx, key, f)
+	return prof, false, err
+}
+
+// wait espera el resultado de f o a que se cancele ctx. El último en
+// irse cancela el pipeline.
+func (g *flightGroup) wait(ctx context.Context, key string, f *flight) (*profile.Profile, error) {
+	select {
+	case <-f.done:
+		return f.prof, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Que un request nuevo no se sume a un pipeline cancelado
+			if g.calls[key] == f {
+				delete(g.calls, key)
+			}
+			f.cancel()
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (g *flightGroup) forget(key string, f *flight) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls[key] == f {
+		delete(g.calls, key)
+	}
+}
+
+// detach crea un contexto que no se cancela con ctx pero conserva su
+// deadline, para que el pipeline compartido no dure más que un request.
+func detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	base := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(base, deadline)
+	}
+	return context.WithCancel(base)
+}
@@ -6,10 +6,13 @@ package httpapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"distroanalyzer/analyze"
@@ -19,22 +22,24 @@ import (
 	"distroanalyzer/profile"
 	"distroanalyzer/score"
 	"distroanalyzer/store"
+	"distroanalyzer/taxonomy"
 )
 
 // Handler maneja las peticiones HTTP.
 type Handler struct {
-	collector  collect.Collector
+	collectors *collect.Registry
 	analyzer   analyze.Analyzer
 	engine     *score.Engine
 	explainer  explain.Explainer
 	cache      cache.Cache
 	store      store.Store
 	templates  *template.Template
+	flights    *flightGroup
 }
 
 // NewHandler crea un nuevo handler HTTP.
 func NewHandler(
-	collector collect.Collector,
+	collectors *collect.Registry,
 	analyzer analyze.Analyzer,
 	engine *score.Engine,
 	explainer explain.Explainer,
@@ -47,26 +52,83 @@ func NewHandler(
 		"mul": func(a, b float64) float64 {
 			return a * b
 		},
+		"evidence":    signalEvidence,
+		"sourceLabel": sourceLabel,
+		"sourceName":  sourceName,
 	}
 
 	tmpl, err := template.New("").
-	Funcs(funcMap).
-	ParseGlob(templatesDir + "/*.html")
+		Funcs(funcMap).
+		ParseGlob(templatesDir + "/*.html")
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse templates: %w", err)
 	}
 
 	return &Handler{
-		collector: collector,
-		analyzer:  analyzer,
-		engine:    engine,
-		explainer: explainer,
-		cache:     cache,
-		store:     store,
-		templates: tmpl,
+		collectors: collectors,
+		analyzer:   analyzer,
+		engine:     engine,
+		explainer:  explainer,
+		cache:      cache,
+		store:      store,
+		templates:  tmpl,
+		flights:    newFlightGroup(),
 	}, nil
 }
 
+// signalEvidence devuelve la evidencia de una señal para los templates.
+// Los perfiles guardados antes de existir la evidencia devuelven una
+// entrada vacía, que se muestra como etiqueta simple.
+func signalEvidence(signals profile.Signals, kind, value string) *profile.SignalEvidence {
+	if ev := signals.EvidenceFor(profile.SignalKind(kind), value); ev != nil {
+		return ev
+	}
+	return &profile.SignalEvidence{Kind: profile.SignalKind(kind), Value: value}
+}
+
+// sourceLabel traduce el origen de una evidencia para mostrarlo.
+func sourceLabel(source profile.EvidenceSource) string {
+	switch source {
+	case profile.SourceBio:
+		return "Bio"
+	case profile.SourceRepo:
+		return "Repositorio"
+	case profile.SourceReadme:
+		return "README"
+	case profile.SourceWebsite:
+		return "Website"
+	case profile.SourceSignals:
+		return "Otras señales"
+	case profile.SourceInferred:
+		return "Inferido por IA"
+	}
+	return string(source)
+}
+
+// sourceName devuelve el nombre legible de una fuente de perfiles. Las
+// instancias self-hosted ("gitlab@gitlab.example.com") muestran el host.
+func sourceName(source string) string {
+	if name, host, ok := strings.Cut(source, "@"); ok {
+		return sourceName(name) + " (" + host + ")"
+	}
+
+	switch source {
+	case "github":
+		return "GitHub"
+	case "gitlab":
+		return "GitLab"
+	case "codeberg":
+		return "Codeberg"
+	case "gitea":
+		return "Gitea"
+	case "forgejo":
+		return "Forgejo"
+	case collect.SourceWeb:
+		return "Sitio web"
+	}
+	return source
+}
+
 // Home muestra el formulario principal.
 func (h *Handler) Home(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -74,13 +136,19 @@ func (h *Handler) Home(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.templates.ExecuteTemplate(w, "index.html", nil); err != nil {
+	data := map[string]interface{}{
+		"Presets": h.engine.Presets().Names(),
+		"Sources": h.collectors.Names(),
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "index.html", data); err != nil {
 		log.Printf("template error: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 	}
 }
 
-// Analyze procesa un perfil de GitHub.
+// Analyze procesa un perfil de la fuente elegida o indicada en la entrada
+// (GitHub por defecto).
 func (h *Handler) Analyze(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -98,84 +166,299 @@ func (h *Handler) Analyze(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Username is required", http.StatusBadRequest)
 		return
 	}
+	// El campo acepta prefijos ("gitlab:alice") y URLs; el select es el default
+	target, err := h.collectors.Resolve(username, r.FormValue("source"))
+	if err != nil {
+		writeError(w, err.Error(), err)
+		return
+	}
 
-	ctx := r.Context()
+	opts := score.Options{
+		Preset:      r.FormValue("preset"),
+		Constraints: constraintsFromForm(r),
+	}
+
+	// "Recalcular" ignora el cache aunque el resultado esté vigente
+	refresh := r.FormValue("refresh") == "true"
+
+	prof, err := h.analyze(r.Context(), target, opts, refresh)
+	if err != nil {
+		writeError(w, fmt.Sprintf("Analysis failed: %v", err), err)
+		return
+	}
+
+	h.renderResult(w, prof)
+}
+
+// analysisLockTTL es cuánto dura como máximo el lock distribuido de un
+// análisis, por si la instancia que lo tiene se cae sin soltarlo.
+const analysisLockTTL = time.Minute
+
+const (
+	// profileCacheTTL es cuánto se cachea un análisis.
+	profileCacheTTL = time.Hour
 
+	// degradedCacheTTL es cuánto se cachea un análisis degradado (el LLM
+	// falló y respondió el analizador de respaldo): lo justo para no
+	// martillar al proveedor caído, y después se reintenta.
+	degradedCacheTTL = 2 * time.Minute
+)
+
+// analyze devuelve el perfil cacheado o corre el pipeline. Con refresh
+// se ignora el cache. Los análisis concurrentes del mismo usuario (con la
+// misma fuente, preset y restricciones) se juntan en uno solo; con un
+// cache compartido, también entre instancias.
+func (h *Handler) analyze(ctx context.Context, target *collect.Target, opts score.Options, refresh bool) (*profile.Profile, error) {
 	// 1. Verificar cache
-	cacheKey := "profile:" + username
-	cached, err := h.cache.Get(ctx, cacheKey)
+	versions := h.versions(opts)
+	key := cacheKey(target.Source, target.ID, opts, versions)
+	if !refresh {
+		if cached := h.cached(ctx, key, versions); cached != nil {
+			log.Printf("cache hit for %s:%s", target.Source, target.ID)
+			return cached, nil
+		}
+	}
+
+	// 2. Ejecutar el pipeline, o esperar al que ya está en curso
+	prof, shared, err := h.flights.Do(ctx, key, func(ctx context.Context) (*profile.Profile, error) {
+		return h.lead(ctx, key, target, opts, refresh)
+	})
 	if err != nil {
-		log.Printf("cache error: %v", err)
+		log.Printf("pipeline error for %s:%s: %v", target.Source, target.ID, err)
+		return nil, err
 	}
+	if shared {
+		log.Printf("joined in-flight analysis for %s:%s", target.Source, target.ID)
+	}
+	return prof, nil
+}
 
-	if cached != nil {
-		log.Printf("cache hit for %s", username)
-		h.renderResult(w, cached)
-		return
+// lead corre el pipeline como líder y guarda el resultado. Si el cache es
+// compartido, primero toma el lock de key: si otra instancia estaba
+// analizando lo mismo, al soltarlo su resultado ya está en el cache (salvo
+// con refresh, que recalcula igual).
+func (h *Handler) lead(ctx context.Context, key string, target *collect.Target, opts score.Options, refresh bool) (*profile.Profile, error) {
+	if locker, ok := h.cache.(cache.Locker); ok {
+		unlock, err := locker.Lock(ctx, key, analysisLockTTL)
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err != nil:
+			// Sin lock se analiza igual: a lo sumo se repite el trabajo
+			log.Printf("analysis lock error: %v", err)
+		default:
+			defer unlock()
+			if refresh {
+				break
+			}
+			if cached := h.cached(ctx, key, h.versions(opts)); cached != nil {
+				log.Printf("cache hit for %s:%s after waiting for another instance", target.Source, target.ID)
+				return cached, nil
+			}
+		}
 	}
 
-	// 2. Ejecutar pipeline completo
-	prof, err := h.runPipeline(ctx, username)
+	prof, err := h.runPipeline(ctx, target, opts)
 	if err != nil {
-		log.Printf("pipeline error for %s: %v", username, err)
-		http.Error(w, fmt.Sprintf("Analysis failed: %v", err), http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
-	// 3. Guardar en cache (1 hora TTL)
-	if err := h.cache.Set(ctx, cacheKey, prof, 1*time.Hour); err != nil {
+	ttl := profileCacheTTL
+	if prof.Signals.Degraded {
+		ttl = degradedCacheTTL
+	}
+	if err := h.cache.Set(ctx, key, prof, ttl); err != nil {
 		log.Printf("failed to cache profile: %v", err)
 	}
 
-	// 4. Persistir en DB
+	// Persistir en DB
 	if err := h.store.Save(ctx, prof); err != nil {
 		log.Printf("failed to save profile: %v", err)
 	}
 
-	// 5. Renderizar resultado
-	h.renderResult(w, prof)
+	return prof, nil
+}
+
+// cached devuelve el perfil de key si está en el cache y fue calculado con
+// las versiones actuales. La clave ya incluye las versiones, pero un
+// catálogo recargado entre armar la clave y calcular deja perfiles con
+// otra versión bajo la clave vieja. El prompt solo cuenta si el perfil lo
+// analizó el LLM.
+func (h *Handler) cached(ctx context.Context, key string, versions profile.Versions) *profile.Profile {
+	cached, err := h.cache.Get(ctx, key)
+	if err != nil {
+		log.Printf("cache error: %v", err)
+		return nil
+	}
+	if cached == nil {
+		return nil
+	}
+	if cached.Signals.AnalyzedBy != analyze.NameAI {
+		versions.Prompt = ""
+	}
+	if cached.Versions != versions {
+		log.Printf("stale cache entry %s (versions %+v, want %+v)", key, cached.Versions, versions)
+		return nil
+	}
+	return cached
+}
+
+// versions son las versiones actuales de motor, catálogo, taxonomía y
+// prompt para un análisis con opts.
+func (h *Handler) versions(opts score.Options) profile.Versions {
+	return profile.Versions{
+		Engine:   h.engine.Version(opts.Preset),
+		Catalog:  h.engine.Catalog().Version,
+		Taxonomy: taxonomy.Default().Version,
+		Prompt:   analyze.PromptVersion,
+	}
 }
 
 // runPipeline ejecuta el flujo completo de análisis.
-func (h *Handler) runPipeline(ctx context.Context, username string) (*profile.Profile, error) {
+func (h *Handler) runPipeline(ctx context.Context, target *collect.Target, opts score.Options) (*profile.Profile, error) {
 	// 1. Collect
-	rawData, err := h.collector.Collect(username)
+	rawData, err := target.Collector.Collect(ctx, target.ID)
 	if err != nil {
 		return nil, fmt.Errorf("collection failed: %w", err)
 	}
 
 	// 2. Analyze
-	signals, err := h.analyzer.Analyze(rawData)
-		if err != nil {
-			return nil, fmt.Errorf("analysis failed: %w", err)
-		}
+	signals, err := h.analyzer.Analyze(ctx, rawData)
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %w", err)
+	}
 
-		// 3. Score
-		scoreOut := h.engine.Score(signals)
-
-		// 4. Explain
-		explanation := h.explainer.Explain(scoreOut.Result, signals)
-		scoreOut.Result.Explanation = explanation
-
-		// 5. Construir Profile completo
-		prof := &profile.Profile{
-			Username:  username,
-			Source:    "github",
-			RawData:   *rawData,
-			Signals:   *signals,
-			Result:    *scoreOut.Result,
-			CreatedAt: time.Now(),
-		}
+	// 3. Score
+	scoreOut, err := h.engine.Score(signals, opts)
+	if err != nil {
+		return nil, fmt.Errorf("scoring failed: %w", err)
+	}
 
-		prof.Recommendation = profile.Recommendation{
-			DistroID:   scoreOut.BestDistroID,
-			DistroName: scoreOut.BestDistroName,
-		}
+	// 4. Explain
+	explanation := h.explainer.Explain(scoreOut.Result, signals)
+	scoreOut.Result.Explanation = explanation
+
+	// 5. Construir Profile completo
+	prof := &profile.Profile{
+		Username:  target.ID,
+		Source:    target.Source,
+		RawData:   *rawData,
+		Signals:   *signals,
+		Result:    *scoreOut.Result,
+		Trace:     scoreOut.Trace,
+		CreatedAt: time.Now(),
+	}
+
+	// Las versiones son las que usó este cálculo, no las actuales: el
+	// catálogo puede haberse recargado mientras tanto
+	prof.Versions = profile.Versions{
+		Engine:   scoreOut.Version,
+		Catalog:  scoreOut.CatalogVersion,
+		Taxonomy: taxonomy.Default().Version,
+	}
+	if signals.AnalyzedBy == analyze.NameAI {
+		prof.Versions.Prompt = analyze.PromptVersion
+	}
+
+	prof.Recommendation = profile.Recommendation{
+		DistroID:   scoreOut.BestDistroID,
+		DistroName: scoreOut.BestDistroName,
+	}
+	if len(scoreOut.Ranking) > 1 {
+		prof.Recommendation.RunnerUps = scoreOut.Ranking[1:]
+	}
+
+	// 6. Limpiar datos pesados
+	prof.ClearLargeData()
+
+	return prof, nil
+}
+
+// constraintsFromForm lee las restricciones del formulario. Cada campo puede
+// repetirse (checkboxes con el mismo name) y usa el nombre del campo JSON.
+func constraintsFromForm(r *http.Request) score.Constraints {
+	return score.Constraints{
+		ExcludeFamilies:        r.Form["exclude_families"],
+		RequireFamilies:        r.Form["require_families"],
+		ExcludeInit:            r.Form["exclude_init"],
+		RequirePackageManagers: r.Form["require_package_managers"],
+		RequireArchitectures:   r.Form["require_architectures"],
+		RequireReleaseModels:   r.Form["require_release_models"],
+	}
+}
+
+// cacheKey arma la clave de cache de un análisis. Incluye la fuente, el
+// preset y las restricciones porque el mismo username da resultados
+// distintos con cada uno, y las versiones para que un cambio de reglas,
+// catálogo, taxonomía o prompt no sirva resultados viejos.
+func cacheKey(source, username string, opts score.Options, versions profile.Versions) string {
+	preset := opts.Preset
+	if preset == "" {
+		preset = score.DefaultPreset
+	}
+	key := "profile:" + versions.Engine + ":" + versions.Catalog + ":" + versions.Taxonomy + ":" + versions.Prompt + ":" +
+		source + ":" + username + ":" + preset
+	if constraints := opts.Constraints.Key(); constraints != "" {
+		key += ":" + constraints
+	}
+	return key
+}
 
-		// 6. Limpiar datos pesados
-		prof.ClearLargeData()
+// writeError responde con el código que corresponde al error. Si la fuente
+// rechazó el pedido por rate limit, Retry-After indica cuándo reintentar.
+func writeError(w http.ResponseWriter, message string, err error) {
+	var rateErr *collect.RateLimitError
+	if errors.As(err, &rateErr) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(rateErr.RetryAfter(time.Now()).Seconds())))
+	}
+	http.Error(w, message, errorStatus(err))
+}
 
-		return prof, nil
+// errorStatus elige el código HTTP según el tipo de error del pipeline.
+func errorStatus(err error) int {
+	var presetErr *score.UnknownPresetError
+	if errors.As(err, &presetErr) {
+		return http.StatusBadRequest
+	}
+	var sourceErr *collect.UnknownSourceError
+	if errors.As(err, &sourceErr) {
+		return http.StatusBadRequest
+	}
+	var inputErr *collect.InvalidInputError
+	if errors.As(err, &inputErr) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, collect.ErrUserNotFound) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, collect.ErrRateLimited) {
+		return http.StatusTooManyRequests
+	}
+	if errors.Is(err, collect.ErrRobotsDisallowed) {
+		return http.StatusForbidden
+	}
+	if errors.Is(err, collect.ErrBlockedAddress) {
+		return http.StatusBadRequest
+	}
+	var noCandidatesErr *score.NoCandidatesError
+	if errors.As(err, &noCandidatesErr) {
+		return http.StatusUnprocessableEntity
+	}
+	// Venció el timeout del request o de una API externa
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	// El LLM no respondió: puede funcionar más tarde
+	var transportErr *analyze.TransportError
+	if errors.As(err, &transportErr) {
+		return http.StatusServiceUnavailable
+	}
+	// El LLM respondió algo inutilizable
+	var schemaErr *analyze.SchemaError
+	if errors.As(err, &schemaErr) {
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
 }
 
 // renderResult renderiza el template de resultado.
@@ -224,7 +507,12 @@ func (h *Handler) AnalyzeJSON(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Username string `json:"username"`
+		Username    string            `json:"username"`
+		Source      string            `json:"source"`
+		Preset      string            `json:"preset"`
+		Constraints score.Constraints `json:"constraints"`
+		Trace       bool              `json:"trace"`
+		Refresh     bool              `json:"refresh"` // ignorar el cache
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -237,27 +525,33 @@ func (h *Handler) AnalyzeJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := r.Context()
+	opts := score.Options{
+		Preset:      req.Preset,
+		Constraints: req.Constraints,
+	}
 
-	// Verificar cache
-	cacheKey := "profile:" + req.Username
-	cached, err := h.cache.Get(ctx, cacheKey)
+	target, err := h.collectors.Resolve(req.Username, req.Source)
 	if err != nil {
-		log.Printf("cache error: %v", err)
+		writeError(w, err.Error(), err)
+		return
 	}
 
-	var prof *profile.Profile
-	if cached != nil {
-		prof = cached
-	} else {
-		prof, err = h.runPipeline(ctx, req.Username)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+	prof, err := h.analyze(r.Context(), target, opts, req.Refresh)
+	if err != nil {
+		writeError(w, err.Error(), err)
+		return
+	}
+
+	// La traza solo se incluye si se pidió explícitamente
+	if !req.Trace && prof.Trace != nil {
+		withoutTrace := *prof
+		withoutTrace.Trace = nil
+		prof = &withoutTrace
+	}
 
-		h.cache.Set(ctx, cacheKey, prof, 1*time.Hour)
-		h.store.Save(ctx, prof)
+	// Avisar a clientes que no leen el cuerpo que el análisis fue degradado
+	if prof.Signals.Degraded {
+		w.Header().Set("X-Analyzer-Degraded", prof.Signals.AnalyzedBy)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
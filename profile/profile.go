@@ -2,7 +2,10 @@
 
 package profile
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // ExperienceLevel representa una estimación gruesa del nivel de experiencia.
 
@@ -58,18 +61,66 @@ type Profile struct {
 	// Recomendacion
 	Recommendation Recommendation
 
+	// Traza del scoring: cómo se llegó a la recomendación.
+	Trace *ScoreTrace `json:"trace,omitempty"`
+
+	// Versiones del motor, catálogo, taxonomía y prompt con que se calculó.
+	// Si alguna cambió, el resultado está desactualizado.
+	Versions Versions
+
 	// Fecha y hora de creación del perfil.
 	CreatedAt time.Time
 }
 
+// Versions identifica las reglas con que se calculó un perfil.
+type Versions struct {
+	// Engine es la versión del código de scoring más el hash del preset.
+	Engine string
+
+	// Catalog es la versión del catálogo de distros.
+	Catalog string
+
+	// Taxonomy es la versión de la taxonomía de términos, que usan tanto
+	// el análisis como el scoring.
+	Taxonomy string
+
+	// Prompt es la versión del prompt y el esquema enviados al LLM.
+	Prompt string
+}
+
 // RawData contiene los datos de entrada recolectados del perfil.
 
 type RawData struct {
 	Bio          string
 	Repositories []string
-	Website      string
-	Location     string
-	Email        string
+
+	// Topics son las etiquetas de los repositorios, sin duplicados.
+	Topics []string
+
+	// Languages son los lenguajes principales de los repositorios, sin
+	// duplicados. Si hay estadísticas, ordenados por bytes de código.
+	Languages []string
+
+	// Repos tiene el detalle de cada repositorio, si la fuente lo ofrece.
+	// Incluye los forks, marcados con Fork; Repositories, Topics y
+	// Languages salen solo de los propios.
+	Repos []RepoStats `json:",omitempty"`
+
+	// Setup es lo detectado en los repos de dotfiles. nil si no hay.
+	Setup *Setup `json:",omitempty"`
+
+	Website  string
+	Location string
+	Email    string
+
+	// Links son los perfiles que un sitio personal declara como propios
+	// (rel="me", sameAs de JSON-LD, h-card) y los repos de forjas que
+	// enlaza sin ser de esos perfiles.
+	Links []string `json:",omitempty"`
+
+	// LinkedProfiles son los perfiles de forjas que un sitio declara como
+	// propios, listos para analizar ("github:octocat").
+	LinkedProfiles []string `json:",omitempty"`
 
 	// ReadmeText puede ser un campo pesado.
 	// Se define como puntero para poder liberarlo (nil)
@@ -77,6 +128,98 @@ type RawData struct {
 	ReadmeText *string
 }
 
+// RepoStats resume un repositorio: lenguajes usados, topics y actividad.
+type RepoStats struct {
+	Name string
+
+	// Language es el lenguaje principal según la forja.
+	Language string
+
+	// Languages son los bytes de código por lenguaje. GitLab solo informa
+	// porcentajes: ahí son proporciones sobre 10000.
+	Languages map[string]int `json:",omitempty"`
+
+	Topics []string `json:",omitempty"`
+
+	// Fork indica que el repo es un fork: no refleja código propio.
+	Fork     bool
+	Stars    int
+	PushedAt time.Time
+}
+
+// Setup describe el entorno que delatan los dotfiles del usuario. Los
+// valores son términos de la taxonomía ("hyprland", "zsh", "pacman") o
+// familias de distros ("arch", "debian").
+type Setup struct {
+	// Repos son los repos de dotfiles analizados.
+	Repos []string
+
+	WindowManagers  []string `json:",omitempty"`
+	Shells          []string `json:",omitempty"`
+	ShellPlugins    []string `json:",omitempty"`
+	PackageManagers []string `json:",omitempty"`
+	Distros         []string `json:",omitempty"`
+
+	// Tools son herramientas de configuración (home-manager, nix-flakes).
+	Tools []string `json:",omitempty"`
+
+	// Files son los archivos que sustentan cada detección, con el repo
+	// como prefijo ("dotfiles/hypr/hyprland.conf").
+	Files []string `json:",omitempty"`
+}
+
+// Terms devuelve todos los valores detectados, para comparar contra
+// categorías de la taxonomía.
+func (s *Setup) Terms() []string {
+	if s == nil {
+		return nil
+	}
+	var terms []string
+	for _, list := range [][]string{s.WindowManagers, s.Shells, s.ShellPlugins, s.PackageManagers, s.Distros, s.Tools} {
+		terms = append(terms, list...)
+	}
+	return terms
+}
+
+// LanguageShare es la proporción del código propio escrita en un lenguaje.
+type LanguageShare struct {
+	Language string
+	Bytes    int
+	Share    float64 // 0-1
+}
+
+// LanguageShares suma los bytes por lenguaje de los repositorios propios
+// (los forks no cuentan) y los devuelve de mayor a menor. Vacío si la
+// fuente no dio estadísticas.
+func (d *RawData) LanguageShares() []LanguageShare {
+	bytes := map[string]int{}
+	total := 0
+	for _, repo := range d.Repos {
+		if repo.Fork {
+			continue
+		}
+		for lang, n := range repo.Languages {
+			bytes[lang] += n
+			total += n
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	shares := make([]LanguageShare, 0, len(bytes))
+	for lang, n := range bytes {
+		shares = append(shares, LanguageShare{Language: lang, Bytes: n, Share: float64(n) / float64(total)})
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Bytes != shares[j].Bytes {
+			return shares[i].Bytes > shares[j].Bytes
+		}
+		return shares[i].Language < shares[j].Language
+	})
+	return shares
+}
+
 // Signals representa señales estructuradas y normalizadas
 // extraídas a partir de RawData.
 
@@ -86,6 +229,84 @@ type Signals struct {
 	ExperienceLevel ExperienceLevel
 	Keywords        []string
 	TechStack       []string
+
+	// Analizador que produjo las señales ("ai", "rules").
+	AnalyzedBy string
+
+	// Degraded indica que las produjo un analizador de respaldo porque el
+	// principal falló o estaba fuera de servicio.
+	Degraded bool
+
+	// Evidencia de cada señal: de dónde salió y con qué confianza.
+	Evidence []SignalEvidence
+
+	// Setup detectado en los dotfiles. Viene tal cual de RawData: es
+	// evidencia directa, no una inferencia del analizador.
+	Setup *Setup `json:",omitempty"`
+}
+
+// EvidenceFor devuelve la evidencia de una señal, o nil si no tiene.
+
+func (s Signals) EvidenceFor(kind SignalKind, value string) *SignalEvidence {
+	for i := range s.Evidence {
+		if s.Evidence[i].Kind == kind && s.Evidence[i].Value == value {
+			return &s.Evidence[i]
+		}
+	}
+	return nil
+}
+
+// SignalKind indica a qué campo de Signals pertenece una evidencia.
+
+type SignalKind string
+
+// Valores posibles para el tipo de señal.
+const (
+	SignalTech       SignalKind = "tech"
+	SignalKeyword    SignalKind = "keyword"
+	SignalTopic      SignalKind = "topic"
+	SignalExperience SignalKind = "experience"
+)
+
+// EvidenceSource indica de qué dato crudo sale una evidencia.
+
+type EvidenceSource string
+
+// Valores posibles para el origen de una evidencia.
+const (
+	SourceBio     EvidenceSource = "bio"
+	SourceRepo    EvidenceSource = "repo"
+	SourceReadme  EvidenceSource = "readme"
+	SourceWebsite EvidenceSource = "website"
+
+	// SourceSignals: la señal se deriva de otras señales (ej: tamaño del stack).
+	SourceSignals EvidenceSource = "signals"
+
+	// SourceInferred: el modelo la infirió sin una mención literal.
+	SourceInferred EvidenceSource = "inferred"
+)
+
+// SignalEvidence explica por qué se extrajo una señal.
+
+type SignalEvidence struct {
+	Kind  SignalKind
+	Value string
+
+	// Confianza en la señal, en el rango [0.0, 1.0].
+	Confidence float64
+
+	// Menciones que la respaldan, de la más a la menos relevante.
+	Evidence []Evidence
+}
+
+// Evidence es una mención concreta en los datos crudos.
+
+type Evidence struct {
+	Source EvidenceSource
+
+	// Fragmento donde aparece la mención, o descripción del dato
+	// ("12 repositorios públicos").
+	Snippet string
 }
 
 //Recomendacion de la distro principal
@@ -93,6 +314,84 @@ type Signals struct {
 type Recommendation struct {
 	DistroID   string
 	DistroName string
+
+	// Alternativas ordenadas por puntaje, sin incluir la distro principal.
+	RunnerUps []RankedDistro
+}
+
+// RankedDistro representa una distro dentro del ranking de recomendaciones.
+
+type RankedDistro struct {
+	DistroID   string
+	DistroName string
+
+	// Puntaje de similitud en el rango [0.0, 1.0].
+	MatchScore float64
+
+	// Puntaje final 0-100 y su categoría, calculados para esta distro.
+	Score    int
+	Category FitCategory
+
+	// Explicación específica de esta distro.
+	Explanation string
+}
+
+// ScoreTrace documenta paso a paso cómo el motor llegó al resultado.
+
+type ScoreTrace struct {
+	Preset     string
+	ConfigHash string
+
+	// Dimensiones calculadas del usuario, por nombre (0-10).
+	Dimensions map[string]int
+
+	// Restricciones duras aplicadas, como "exclude_init=systemd".
+	Constraints []string
+
+	// Todas las distros evaluadas, en orden de ranking. Las descartadas
+	// antes del ranking aparecen al final con SkipReason.
+	Candidates []CandidateTrace
+}
+
+// CandidateTrace contiene los términos intermedios de una distro candidata.
+
+type CandidateTrace struct {
+	DistroID   string
+	DistroName string
+
+	// Motivo por el que la distro no entró al ranking (vacío si entró).
+	SkipReason string
+
+	Distance        float64
+	Similarity      float64
+	PopularityTerm  float64
+	TrendMultiplier float64
+
+	// Match score después de aplicar Penalties.
+	MatchScore float64
+	Penalties  []PenaltyTrace
+
+	// Posición en el ranking devuelto (1 = principal, 0 = fuera del top N).
+	// El ranking se ordena por FinalScore; las distros descartadas antes
+	// de ordenar no tienen FinalScore ni Adjustments.
+	Rank        int
+	FinalScore  int
+	Adjustments []AdjustmentTrace
+}
+
+// PenaltyTrace registra un factor multiplicativo aplicado al match score.
+
+type PenaltyTrace struct {
+	Rule   string
+	Factor float64
+}
+
+// AdjustmentTrace registra una regla de ajuste del puntaje final que se
+// disparó y cuántos puntos sumó o restó.
+
+type AdjustmentTrace struct {
+	Rule  string
+	Delta int
 }
 
 // Result representa el resultado final del análisis del perfil.
@@ -109,6 +408,11 @@ type Result struct {
 
 	// Nivel de confianza del análisis, esperado en el rango [0.0, 1.0].
 	Confidence float64
+
+	// Preset de scoring y hash de su configuración, para saber con qué
+	// reglas exactas se obtuvo este resultado.
+	Preset     string
+	ConfigHash string
 }
 
 // ClearLargeData elimina datos crudos voluminosos que ya no son necesarios.
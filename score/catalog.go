@@ -0,0 +1,171 @@
+package score
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Catalog es la base versionada de distros que usa el motor.
+//
+// Se mantiene en un archivo de datos para que cualquier colaborador pueda
+// ajustar atributos sin tocar código Go.
+type Catalog struct {
+	// Version identifica la revisión del catálogo (ej: "2026.10.1").
+	Version string `json:"version"`
+
+	Distros []Distro `json:"distros"`
+}
+
+// validReleaseModels son los modelos de publicación que acepta el catálogo.
+var validReleaseModels = map[string]bool{
+	"rolling": true,
+	"fixed":   true,
+	"lts":     true,
+}
+
+// defaultCatalogData es el catálogo incluido en el binario.
+//
+//go:embed data/distros.json
+var defaultCatalogData []byte
+
+// DefaultCatalog devuelve el catálogo incluido en el binario.
+func DefaultCatalog() (*Catalog, error) {
+	catalog, err := ParseCatalog(defaultCatalogData)
+	if err != nil {
+		return nil, fmt.Errorf("embedded catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+// LoadCatalog lee y valida un catálogo desde un archivo JSON.
+func LoadCatalog(path string) (*Catalog, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".json" {
+		return nil, fmt.Errorf("unsupported catalog format %q (expected .json)", ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+
+	catalog, err := ParseCatalog(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return catalog, nil
+}
+
+// ParseCatalog decodifica un catálogo JSON y valida su esquema.
+// Los campos desconocidos se rechazan para detectar errores de tipeo.
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var catalog Catalog
+	if err := decodeStrict(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid catalog JSON: %w", err)
+	}
+
+	if err := catalog.Validate(); err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
+
+// Validate verifica que el catálogo cumpla el esquema esperado.
+// Devuelve todos los problemas encontrados, no solo el primero.
+func (c *Catalog) Validate() error {
+	var errs []error
+
+	if strings.TrimSpace(c.Version) == "" {
+		errs = append(errs, errors.New("catalog version is required"))
+	}
+	if len(c.Distros) == 0 {
+		errs = append(errs, errors.New("catalog has no distros"))
+	}
+
+	seen := make(map[string]bool, len(c.Distros))
+	for i, d := range c.Distros {
+		where := fmt.Sprintf("distros[%d]", i)
+		if d.ID != "" {
+			where += " (" + d.ID + ")"
+		}
+
+		if d.ID == "" {
+			errs = append(errs, fmt.Errorf("%s: id is required", where))
+		} else if seen[d.ID] {
+			errs = append(errs, fmt.Errorf("%s: duplicate id", where))
+		}
+		seen[d.ID] = true
+
+		if d.Name == "" {
+			errs = append(errs, fmt.Errorf("%s: name is required", where))
+		}
+
+		attrs := []struct {
+			name  string
+			value int
+		}{
+			{"rolling", d.Rolling},
+			{"easy", d.Easy},
+			{"diy", d.DIY},
+			{"performance", d.Performance},
+			{"dev_focus", d.DevFocus},
+			{"privacy", d.Privacy},
+			{"pentest", d.Pentest},
+			{"lightweight", d.Lightweight},
+		}
+		for _, attr := range attrs {
+			if attr.value < 0 || attr.value > 10 {
+				errs = append(errs, fmt.Errorf("%s: %s must be in 0-10, got %d", where, attr.name, attr.value))
+			}
+		}
+
+		if d.Popularity < 0 {
+			errs = append(errs, fmt.Errorf("%s: popularity must be >= 0", where))
+		}
+
+		lists := []struct {
+			name   string
+			values []string
+		}{
+			{"base_family", d.BaseFamily},
+			{"init", d.Init},
+			{"package_manager", d.PackageManager},
+			{"architectures", d.Architectures},
+			{"release_model", d.ReleaseModel},
+		}
+		for _, list := range lists {
+			if len(list.values) == 0 {
+				errs = append(errs, fmt.Errorf("%s: %s must not be empty", where, list.name))
+			}
+			for _, v := range list.values {
+				// las restricciones comparan en minúsculas y sin espacios
+				if v == "" || v != strings.ToLower(strings.TrimSpace(v)) {
+					errs = append(errs, fmt.Errorf("%s: %s value %q must be lowercase and non-empty", where, list.name, v))
+				}
+			}
+		}
+		for _, model := range d.ReleaseModel {
+			if !validReleaseModels[model] {
+				errs = append(errs, fmt.Errorf("%s: unknown release_model %q (expected rolling, fixed or lts)", where, model))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// MaxPopularity devuelve la popularidad más alta del catálogo.
+// Se usa para normalizar el término de popularidad del scoring.
+func (c *Catalog) MaxPopularity() int {
+	max := 0
+	for _, d := range c.Distros {
+		if d.Popularity > max {
+			max = d.Popularity
+		}
+	}
+	return max
+}
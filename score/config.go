@@ -0,0 +1,332 @@
+package score
+
+import (
+	"bytes"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// DefaultPreset es el preset usado cuando la petición no indica ninguno.
+const DefaultPreset = "default"
+
+// EngineConfig contiene todos los pesos, umbrales y listas de keywords
+// que usa el motor. Cada preset es un EngineConfig completo.
+type EngineConfig struct {
+	Match       MatchConfig      `json:"match"`
+	Penalties   PenaltyConfig    `json:"penalties"`
+	Adjustments AdjustmentConfig `json:"adjustments"`
+	Categories  CategoryConfig   `json:"categories"`
+	Dimensions  DimensionConfig  `json:"dimensions"`
+}
+
+// MatchConfig controla el cálculo del match score de cada distro.
+type MatchConfig struct {
+	Alpha float64 `json:"alpha"` // peso de la similitud geométrica
+	Beta  float64 `json:"beta"`  // peso de la popularidad
+
+	TrendUpMultiplier   float64 `json:"trend_up_multiplier"`
+	TrendDownMultiplier float64 `json:"trend_down_multiplier"`
+
+	// Distros con menos popularidad se descartan para perfiles senior.
+	SeniorMinPopularity int `json:"senior_min_popularity"`
+}
+
+// PenaltyConfig son los factores multiplicativos aplicados al match score
+// de perfiles senior.
+type PenaltyConfig struct {
+	// Distro genérica para un senior muy orientado a desarrollo.
+	SeniorDevMinScore    int     `json:"senior_dev_min_score"`
+	SeniorDevMaxDevFocus int     `json:"senior_dev_max_dev_focus"`
+	SeniorDevFactor      float64 `json:"senior_dev_factor"`
+
+	// Distro demasiado "easy" para un senior con perfil DIY.
+	SeniorDIYMinScore int     `json:"senior_diy_min_score"`
+	SeniorDIYMinEasy  int     `json:"senior_diy_min_easy"`
+	SeniorDIYFactor   float64 `json:"senior_diy_factor"`
+}
+
+// AdjustmentConfig son los ajustes aditivos (en puntos 0-100) de
+// calculateFinalScore. Los deltas negativos son penalizaciones.
+type AdjustmentConfig struct {
+	JuniorEasyMin   int `json:"junior_easy_min"`
+	JuniorEasyDelta int `json:"junior_easy_delta"`
+	JuniorDIYMin    int `json:"junior_diy_min"`
+	JuniorDIYDelta  int `json:"junior_diy_delta"`
+
+	SeniorDevFocusMin   int `json:"senior_dev_focus_min"`
+	SeniorDevFocusDelta int `json:"senior_dev_focus_delta"`
+	SeniorDIYMin        int `json:"senior_diy_min"`
+	SeniorDIYDelta      int `json:"senior_diy_delta"`
+	SeniorTooEasyMin    int `json:"senior_too_easy_min"`
+	SeniorTooEasyMaxDIY int `json:"senior_too_easy_max_diy"`
+	SeniorTooEasyDelta  int `json:"senior_too_easy_delta"`
+
+	// Usuario con dimensión alta frente a una distro débil en ella.
+	DevMismatchUserMin    int `json:"dev_mismatch_user_min"`
+	DevMismatchDistroMax  int `json:"dev_mismatch_distro_max"`
+	DevMismatchDelta      int `json:"dev_mismatch_delta"`
+	PerfMismatchUserMin   int `json:"perf_mismatch_user_min"`
+	PerfMismatchDistroMax int `json:"perf_mismatch_distro_max"`
+	PerfMismatchDelta     int `json:"perf_mismatch_delta"`
+
+	// Bonus por coincidencia casi exacta en varias dimensiones.
+	PerfectMatchTolerance int `json:"perfect_match_tolerance"`
+	PerfectMatch2Delta    int `json:"perfect_match_2_delta"`
+	PerfectMatch3Delta    int `json:"perfect_match_3_delta"`
+
+	// Distros muy nicho, o en declive y poco populares.
+	NichePopularityBelow     int `json:"niche_popularity_below"`
+	NicheDelta               int `json:"niche_delta"`
+	DecliningPopularityBelow int `json:"declining_popularity_below"`
+	DecliningDelta           int `json:"declining_delta"`
+}
+
+// CategoryConfig define los umbrales del puntaje final para cada categoría.
+type CategoryConfig struct {
+	StrongMin    int `json:"strong_min"`
+	PotentialMin int `json:"potential_min"`
+}
+
+// DimensionConfig agrupa las reglas de cálculo de cada dimensión del usuario.
+//
+// Las listas contienen términos o categorías de la taxonomía: un valor
+// coincide si es el término, un alias, o desciende de la categoría
+// ("hyprland" coincide con "diy"). Cada coincidencia aporta su delta
+// multiplicado por el peso del término.
+type DimensionConfig struct {
+	Rolling     RollingConfig          `json:"rolling"`
+	DIY         DIYConfig              `json:"diy"`
+	Performance PerformanceConfig      `json:"performance"`
+	Dev         DevConfig              `json:"dev"`
+	Privacy     KeywordDimensionConfig `json:"privacy"`
+	Pentest     KeywordDimensionConfig `json:"pentest"`
+	LowSpec     KeywordDimensionConfig `json:"low_spec"`
+}
+
+// RollingConfig controla la preferencia rolling vs LTS.
+type RollingConfig struct {
+	Base               int      `json:"base"`
+	BleedingTech       []string `json:"bleeding_tech"`
+	BleedingTechDelta  int      `json:"bleeding_tech_delta"`
+	SeniorDelta        int      `json:"senior_delta"`
+	StableKeywords     []string `json:"stable_keywords"`
+	StableKeywordDelta int      `json:"stable_keyword_delta"`
+
+	// Distros que delatan los dotfiles (pacman.conf → arch): las rolling
+	// suman SetupDistroDelta y las estables lo restan.
+	SetupRollingDistros []string `json:"setup_rolling_distros"`
+	SetupStableDistros  []string `json:"setup_stable_distros"`
+	SetupDistroDelta    int      `json:"setup_distro_delta"`
+}
+
+// DIYConfig controla la preferencia por personalización vs simplicidad.
+type DIYConfig struct {
+	Base             int      `json:"base"`
+	Keywords         []string `json:"keywords"`
+	KeywordDelta     int      `json:"keyword_delta"`
+	EasyKeywords     []string `json:"easy_keywords"`
+	EasyKeywordDelta int      `json:"easy_keyword_delta"`
+	ScriptingLangs   []string `json:"scripting_langs"`
+	ScriptingMin     int      `json:"scripting_min"`
+	ScriptingDelta   int      `json:"scripting_delta"`
+
+	// Lo detectado en los dotfiles (window managers, configuración
+	// declarativa) suma SetupDelta por coincidencia, hasta SetupMaxDelta.
+	SetupTerms    []string `json:"setup_terms"`
+	SetupDelta    int      `json:"setup_delta"`
+	SetupMaxDelta int      `json:"setup_max_delta"`
+}
+
+// PerformanceConfig controla la necesidad de rendimiento/gaming.
+type PerformanceConfig struct {
+	Base         int      `json:"base"`
+	Keywords     []string `json:"keywords"`
+	KeywordDelta int      `json:"keyword_delta"`
+	Tech         []string `json:"tech"`
+	TechDelta    int      `json:"tech_delta"`
+}
+
+// DevConfig controla la orientación a desarrollo/DevOps.
+type DevConfig struct {
+	Base                 int      `json:"base"`
+	CriticalKeywords     []string `json:"critical_keywords"`
+	CriticalKeywordDelta int      `json:"critical_keyword_delta"`
+	Tech                 []string `json:"tech"`
+	TechDelta            int      `json:"tech_delta"`
+	Keywords             []string `json:"keywords"`
+	KeywordDelta         int      `json:"keyword_delta"`
+}
+
+// KeywordDimensionConfig controla una dimensión calculada solo a partir de
+// keywords, topics y tech stack (privacidad, pentesting, hardware).
+type KeywordDimensionConfig struct {
+	Base         int      `json:"base"`
+	Keywords     []string `json:"keywords"`
+	KeywordDelta int      `json:"keyword_delta"`
+	TopicDelta   int      `json:"topic_delta"`
+	Tech         []string `json:"tech"`
+	TechDelta    int      `json:"tech_delta"`
+}
+
+// Hash devuelve un identificador corto y estable de la configuración.
+// Dos resultados con el mismo hash fueron calculados con las mismas reglas.
+func (c *EngineConfig) Hash() string {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// Validate verifica que los pesos tengan valores utilizables.
+func (c *EngineConfig) Validate() error {
+	var errs []error
+
+	if c.Match.Alpha < 0 || c.Match.Beta < 0 {
+		errs = append(errs, errors.New("match.alpha and match.beta must be >= 0"))
+	}
+	if c.Match.Alpha+c.Match.Beta == 0 {
+		errs = append(errs, errors.New("match.alpha + match.beta must be > 0"))
+	}
+	if c.Match.TrendUpMultiplier <= 0 || c.Match.TrendDownMultiplier <= 0 {
+		errs = append(errs, errors.New("trend multipliers must be > 0"))
+	}
+	if c.Penalties.SeniorDevFactor <= 0 || c.Penalties.SeniorDIYFactor <= 0 {
+		errs = append(errs, errors.New("penalty factors must be > 0"))
+	}
+	if c.Categories.PotentialMin > c.Categories.StrongMin {
+		errs = append(errs, errors.New("categories.potential_min must be <= categories.strong_min"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Presets es el conjunto de configuraciones con nombre disponibles.
+type Presets struct {
+	configs map[string]*EngineConfig
+}
+
+// presetsFile es el formato del archivo de presets: un preset "default"
+// completo y presets adicionales que solo declaran lo que cambian.
+type presetsFile struct {
+	Default json.RawMessage            `json:"default"`
+	Presets map[string]json.RawMessage `json:"presets"`
+}
+
+// defaultPresetsData son los presets incluidos en el binario.
+//
+//go:embed data/presets.json
+var defaultPresetsData []byte
+
+// DefaultPresets devuelve los presets incluidos en el binario.
+func DefaultPresets() (*Presets, error) {
+	presets, err := ParsePresets(defaultPresetsData)
+	if err != nil {
+		return nil, fmt.Errorf("embedded presets: %w", err)
+	}
+	return presets, nil
+}
+
+// LoadPresets lee y valida un archivo de presets JSON.
+func LoadPresets(path string) (*Presets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presets: %w", err)
+	}
+
+	presets, err := ParsePresets(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return presets, nil
+}
+
+// ParsePresets decodifica un archivo de presets. Cada preset adicional se
+// aplica encima de "default", así que solo necesita los campos que cambia.
+func ParsePresets(data []byte) (*Presets, error) {
+	var file presetsFile
+	if err := decodeStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid presets JSON: %w", err)
+	}
+	if len(file.Default) == 0 {
+		return nil, errors.New("presets: \"default\" is required")
+	}
+
+	base := &EngineConfig{}
+	if err := decodeStrict(file.Default, base); err != nil {
+		return nil, fmt.Errorf("preset %q: %w", DefaultPreset, err)
+	}
+
+	presets := &Presets{configs: map[string]*EngineConfig{DefaultPreset: base}}
+
+	for name, override := range file.Presets {
+		if name == DefaultPreset {
+			return nil, fmt.Errorf("preset %q must be declared at the top level", DefaultPreset)
+		}
+
+		cfg, err := base.clone()
+		if err != nil {
+			return nil, err
+		}
+		if err := decodeStrict(override, cfg); err != nil {
+			return nil, fmt.Errorf("preset %q: %w", name, err)
+		}
+		presets.configs[name] = cfg
+	}
+
+	for name, cfg := range presets.configs {
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("preset %q: %w", name, err)
+		}
+	}
+
+	return presets, nil
+}
+
+// Get devuelve el preset con ese nombre. Un nombre vacío equivale a "default".
+func (p *Presets) Get(name string) (*EngineConfig, bool) {
+	if name == "" {
+		name = DefaultPreset
+	}
+	cfg, ok := p.configs[name]
+	return cfg, ok
+}
+
+// Names devuelve los nombres de los presets, con "default" primero.
+func (p *Presets) Names() []string {
+	names := make([]string, 0, len(p.configs))
+	for name := range p.configs {
+		if name != DefaultPreset {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultPreset}, names...)
+}
+
+// clone devuelve una copia profunda (las listas de keywords no se comparten).
+func (c *EngineConfig) clone() (*EngineConfig, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var out EngineConfig
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// decodeStrict decodifica JSON rechazando campos desconocidos.
+func decodeStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
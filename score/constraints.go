@@ -0,0 +1,174 @@
+package score
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"distroanalyzer/profile"
+)
+
+// Constraints son requisitos duros del usuario ("sin systemd", "que corra en
+// ARM"). A diferencia de las dimensiones no ajustan el puntaje: una distro
+// que no cumple alguno queda fuera antes del ranking.
+//
+// Los valores se comparan en minúsculas contra los atributos categóricos del
+// catálogo (BaseFamily, Init, PackageManager, Architectures, ReleaseModel).
+type Constraints struct {
+	// ExcludeFamilies descarta las distros de estas familias, incluida la
+	// propia base: "arch" excluye Arch y todas sus derivadas.
+	ExcludeFamilies []string `json:"exclude_families,omitempty"`
+
+	// RequireFamilies exige pertenecer a alguna de estas familias.
+	RequireFamilies []string `json:"require_families,omitempty"`
+
+	// ExcludeInit descarta las distros que no ofrecen ningún init fuera de
+	// estos. ["systemd"] = "systemd-free".
+	ExcludeInit []string `json:"exclude_init,omitempty"`
+
+	// RequirePackageManagers exige alguno de estos gestores de paquetes.
+	RequirePackageManagers []string `json:"require_package_managers,omitempty"`
+
+	// RequireArchitectures exige soporte oficial para todas estas arquitecturas.
+	RequireArchitectures []string `json:"require_architectures,omitempty"`
+
+	// RequireReleaseModels exige alguno de estos modelos ("rolling", "fixed", "lts").
+	RequireReleaseModels []string `json:"require_release_models,omitempty"`
+}
+
+// constraint describe una restricción: qué valores pidió el usuario y si una
+// distro la cumple. El orden de constraints es el orden en que se aplican.
+type constraint struct {
+	name   string
+	values func(Constraints) []string
+	allows func(d Distro, values []string) bool
+}
+
+var constraints = []constraint{
+	{"exclude_families", func(c Constraints) []string { return c.ExcludeFamilies },
+		func(d Distro, values []string) bool { return !containsAny(d.BaseFamily, values) }},
+	{"require_families", func(c Constraints) []string { return c.RequireFamilies },
+		func(d Distro, values []string) bool { return containsAny(d.BaseFamily, values) }},
+	{"exclude_init", func(c Constraints) []string { return c.ExcludeInit },
+		func(d Distro, values []string) bool {
+			for _, system := range d.Init {
+				if !slices.Contains(values, system) {
+					return true
+				}
+			}
+			return false
+		}},
+	{"require_package_managers", func(c Constraints) []string { return c.RequirePackageManagers },
+		func(d Distro, values []string) bool { return containsAny(d.PackageManager, values) }},
+	{"require_architectures", func(c Constraints) []string { return c.RequireArchitectures },
+		func(d Distro, values []string) bool {
+			for _, arch := range values {
+				if !slices.Contains(d.Architectures, arch) {
+					return false
+				}
+			}
+			return true
+		}},
+	{"require_release_models", func(c Constraints) []string { return c.RequireReleaseModels },
+		func(d Distro, values []string) bool { return containsAny(d.ReleaseModel, values) }},
+}
+
+// containsAny indica si have contiene alguno de want.
+func containsAny(have, want []string) bool {
+	for _, w := range want {
+		if slices.Contains(have, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalized devuelve una copia con valores en minúsculas, sin vacíos ni
+// duplicados y ordenados, para que dos pedidos equivalentes sean iguales.
+func (c Constraints) normalized() Constraints {
+	clean := func(values []string) []string {
+		var out []string
+		for _, v := range values {
+			v = strings.ToLower(strings.TrimSpace(v))
+			if v != "" && !slices.Contains(out, v) {
+				out = append(out, v)
+			}
+		}
+		slices.Sort(out)
+		return out
+	}
+	return Constraints{
+		ExcludeFamilies:        clean(c.ExcludeFamilies),
+		RequireFamilies:        clean(c.RequireFamilies),
+		ExcludeInit:            clean(c.ExcludeInit),
+		RequirePackageManagers: clean(c.RequirePackageManagers),
+		RequireArchitectures:   clean(c.RequireArchitectures),
+		RequireReleaseModels:   clean(c.RequireReleaseModels),
+	}
+}
+
+// Terms devuelve las restricciones activas como "nombre=v1,v2", en orden de
+// aplicación. Vacío si no hay ninguna.
+func (c Constraints) Terms() []string {
+	c = c.normalized()
+	var terms []string
+	for _, con := range constraints {
+		if values := con.values(c); len(values) > 0 {
+			terms = append(terms, con.name+"="+strings.Join(values, ","))
+		}
+	}
+	return terms
+}
+
+// Key devuelve una representación canónica de las restricciones, apta para
+// claves de cache. Vacío si no hay ninguna.
+func (c Constraints) Key() string {
+	return strings.Join(c.Terms(), ";")
+}
+
+// NoCandidatesError indica que las restricciones descartaron todas las distros.
+// Constraint es la restricción que eliminó a las últimas candidatas.
+type NoCandidatesError struct {
+	Constraint string
+	Values     []string
+}
+
+func (e *NoCandidatesError) Error() string {
+	return fmt.Sprintf("no distro satisfies constraint %s=%s", e.Constraint, strings.Join(e.Values, ","))
+}
+
+// filterCandidates aplica las restricciones en orden y devuelve las distros
+// que las cumplen, junto con la traza de las descartadas. Cada distro se
+// descarta por la primera restricción que no cumple.
+func filterCandidates(c Constraints, distros []Distro) ([]Distro, []profile.CandidateTrace, error) {
+	c = c.normalized()
+	remaining := distros
+	var skipped []profile.CandidateTrace
+
+	for _, con := range constraints {
+		values := con.values(c)
+		if len(values) == 0 {
+			continue
+		}
+
+		kept := make([]Distro, 0, len(remaining))
+		for _, d := range remaining {
+			if con.allows(d, values) {
+				kept = append(kept, d)
+				continue
+			}
+			skipped = append(skipped, profile.CandidateTrace{
+				DistroID:   d.ID,
+				DistroName: d.Name,
+				SkipReason: "constraint:" + con.name,
+			})
+		}
+
+		if len(kept) == 0 {
+			return nil, skipped, &NoCandidatesError{Constraint: con.name, Values: values}
+		}
+		remaining = kept
+	}
+
+	return remaining, skipped, nil
+}
@@ -0,0 +1,186 @@
+package score
+
+import (
+	"math"
+
+	"distroanalyzer/profile"
+	"distroanalyzer/taxonomy"
+)
+
+// UserDimensions representa las dimensiones calculadas del usuario.
+type UserDimensions struct {
+	RollingScore     int // 0-10: LTS(0) → Rolling(10)
+	DIYScore         int // 0-10: Easy(0) → DIY(10)
+	PerformanceScore int // 0-10: necesidad de rendimiento
+	DevScore         int // 0-10: orientación desarrollo
+	PrivacyScore     int // 0-10: enfoque en privacidad/seguridad
+	PentestScore     int // 0-10: interés en pentesting/seguridad ofensiva
+	LowSpecScore     int // 0-10: hardware viejo o limitado
+}
+
+// dimension describe un eje del espacio de matching: cómo leer el valor del
+// usuario y el atributo equivalente de la distro. Agregar una dimensión es
+// agregar una entrada a dimensions; distancia, trazas y bonus la recorren.
+type dimension struct {
+	name   string
+	user   func(UserDimensions) int
+	distro func(Distro) int
+
+	// base devuelve el valor neutro de las dimensiones de nicho. Esas solo
+	// cuentan para el bonus de match perfecto cuando el usuario muestra
+	// interés (valor por encima del neutro); si no, casi cualquier distro
+	// "coincidiría" con un usuario que no habla de privacidad.
+	base func(*EngineConfig) int
+}
+
+var dimensions = []dimension{
+	{"rolling", func(u UserDimensions) int { return u.RollingScore }, func(d Distro) int { return d.Rolling }, nil},
+	{"diy", func(u UserDimensions) int { return u.DIYScore }, func(d Distro) int { return d.DIY }, nil},
+	{"performance", func(u UserDimensions) int { return u.PerformanceScore }, func(d Distro) int { return d.Performance }, nil},
+	{"dev", func(u UserDimensions) int { return u.DevScore }, func(d Distro) int { return d.DevFocus }, nil},
+	{"privacy", func(u UserDimensions) int { return u.PrivacyScore }, func(d Distro) int { return d.Privacy },
+		func(c *EngineConfig) int { return c.Dimensions.Privacy.Base }},
+	{"pentest", func(u UserDimensions) int { return u.PentestScore }, func(d Distro) int { return d.Pentest },
+		func(c *EngineConfig) int { return c.Dimensions.Pentest.Base }},
+	{"low_spec", func(u UserDimensions) int { return u.LowSpecScore }, func(d Distro) int { return d.Lightweight },
+		func(c *EngineConfig) int { return c.Dimensions.LowSpec.Base }},
+}
+
+// maxDistance es la distancia euclidiana máxima posible entre usuario y
+// distro: cada dimensión va de 0 a 10, así que sqrt(n * 10^2).
+func maxDistance() float64 {
+	return 10 * math.Sqrt(float64(len(dimensions)))
+}
+
+// distanceTo calcula la distancia euclidiana entre el usuario y la distro.
+func (d UserDimensions) distanceTo(distro Distro) float64 {
+	sum := 0.0
+	for _, dim := range dimensions {
+		diff := float64(dim.user(d) - dim.distro(distro))
+		sum += diff * diff
+	}
+	return math.Sqrt(sum)
+}
+
+// Map devuelve las dimensiones por nombre, para trazas y serialización.
+func (d UserDimensions) Map() map[string]int {
+	out := make(map[string]int, len(dimensions))
+	for _, dim := range dimensions {
+		out[dim.name] = dim.user(d)
+	}
+	return out
+}
+
+// calculateDimensions extrae dimensiones del perfil a partir de señales.
+func calculateDimensions(cfg *EngineConfig, signals *profile.Signals) UserDimensions {
+	dims := UserDimensions{}
+
+	// 1. Ciclo de vida (Rolling vs LTS)
+	dims.RollingScore = calculateRollingPreference(cfg.Dimensions.Rolling, signals)
+
+	// 2. Personalización (DIY vs Easy)
+	dims.DIYScore = calculateDIYPreference(cfg.Dimensions.DIY, signals)
+
+	// 3. Performance/Gaming
+	dims.PerformanceScore = calculatePerformanceNeed(cfg.Dimensions.Performance, signals)
+
+	// 4. Developer focus
+	dims.DevScore = calculateDevFocus(cfg.Dimensions.Dev, signals)
+
+	// 5. Privacidad/seguridad
+	dims.PrivacyScore = calculateKeywordDimension(cfg.Dimensions.Privacy, signals)
+
+	// 6. Pentesting
+	dims.PentestScore = calculateKeywordDimension(cfg.Dimensions.Pentest, signals)
+
+	// 7. Hardware viejo o limitado
+	dims.LowSpecScore = calculateKeywordDimension(cfg.Dimensions.LowSpec, signals)
+
+	return dims
+}
+
+// weightedDelta escala delta por el peso de los valores que pertenecen a
+// alguna de las categorías de la taxonomía.
+func weightedDelta(values, categories []string, delta int) int {
+	weight := taxonomy.Default().MatchWeight(values, categories)
+	return int(math.Round(weight * float64(delta)))
+}
+
+// calculateRollingPreference detecta preferencia por rolling/bleeding edge.
+func calculateRollingPreference(cfg RollingConfig, signals *profile.Signals) int {
+	score := cfg.Base
+
+	// Tecnologías bleeding edge
+	score += weightedDelta(signals.TechStack, cfg.BleedingTech, cfg.BleedingTechDelta)
+
+	// Experience senior = más tolerancia al cambio
+	if signals.ExperienceLevel == profile.ExpSenior {
+		score += cfg.SeniorDelta
+	}
+
+	// Keywords de estabilidad
+	score += weightedDelta(signals.Keywords, cfg.StableKeywords, cfg.StableKeywordDelta)
+
+	// La distro que el usuario ya usa, según sus dotfiles
+	if signals.Setup != nil {
+		score += weightedDelta(signals.Setup.Distros, cfg.SetupRollingDistros, cfg.SetupDistroDelta)
+		score -= weightedDelta(signals.Setup.Distros, cfg.SetupStableDistros, cfg.SetupDistroDelta)
+	}
+
+	return clamp(score, 0, 10)
+}
+
+// calculateDIYPreference detecta si prefiere customización o simplicidad.
+func calculateDIYPreference(cfg DIYConfig, signals *profile.Signals) int {
+	score := cfg.Base
+
+	// Keywords clave
+	score += weightedDelta(signals.Keywords, cfg.Keywords, cfg.KeywordDelta)
+	score += weightedDelta(signals.Keywords, cfg.EasyKeywords, cfg.EasyKeywordDelta)
+
+	// Tech stack de scripting
+	scriptCount := taxonomy.Default().MatchWeight(signals.TechStack, cfg.ScriptingLangs)
+	if scriptCount >= float64(cfg.ScriptingMin) {
+		score += cfg.ScriptingDelta
+	}
+
+	// Dotfiles: tiling WMs, Nix, Portage...
+	score += min(weightedDelta(signals.Setup.Terms(), cfg.SetupTerms, cfg.SetupDelta), cfg.SetupMaxDelta)
+
+	return clamp(score, 0, 10)
+}
+
+// calculatePerformanceNeed detecta necesidad de alto rendimiento/gaming.
+func calculatePerformanceNeed(cfg PerformanceConfig, signals *profile.Signals) int {
+	score := cfg.Base
+
+	score += weightedDelta(signals.Keywords, cfg.Keywords, cfg.KeywordDelta)
+	score += weightedDelta(signals.TechStack, cfg.Tech, cfg.TechDelta)
+
+	return clamp(score, 0, 10)
+}
+
+// calculateDevFocus detecta orientación a desarrollo/DevOps.
+func calculateDevFocus(cfg DevConfig, signals *profile.Signals) int {
+	score := cfg.Base
+
+	// Bonus especial por keywords de alto nivel
+	score += weightedDelta(signals.Keywords, cfg.CriticalKeywords, cfg.CriticalKeywordDelta)
+
+	score += weightedDelta(signals.TechStack, cfg.Tech, cfg.TechDelta)
+	score += weightedDelta(signals.Keywords, cfg.Keywords, cfg.KeywordDelta)
+
+	return clamp(score, 0, 10)
+}
+
+// calculateKeywordDimension calcula una dimensión que solo depende de
+// coincidencias de keywords y tech stack con categorías de la taxonomía.
+func calculateKeywordDimension(cfg KeywordDimensionConfig, signals *profile.Signals) int {
+	score := cfg.Base
+
+	score += weightedDelta(signals.Keywords, cfg.Keywords, cfg.KeywordDelta)
+	score += weightedDelta(signals.TechStack, cfg.Tech, cfg.TechDelta)
+	score += weightedDelta(signals.Topics, cfg.Keywords, cfg.TopicDelta)
+
+	return clamp(score, 0, 10)
+}
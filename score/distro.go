@@ -1,16 +1,28 @@
 package score
 
+import "fmt"
+
 // Distro representa una distribución de Linux con sus características.
 type Distro struct {
-	ID          string
-	Name        string
-	Rolling     int  // 0-10: cuán rolling/bleeding edge es
-	Easy        int  // 0-10: facilidad de uso
-	DIY         int  // 0-10: nivel de personalización
-	Performance int  // 0-10: optimización de rendimiento
-	DevFocus    int  // 0-10: orientación a desarrollo
-	Popularity  int  // HPD (Hits Per Day) de DistroWatch
-	Trend       Trend
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Rolling     int    `json:"rolling"`     // 0-10: cuán rolling/bleeding edge es
+	Easy        int    `json:"easy"`        // 0-10: facilidad de uso
+	DIY         int    `json:"diy"`         // 0-10: nivel de personalización
+	Performance int    `json:"performance"` // 0-10: optimización de rendimiento
+	DevFocus    int    `json:"dev_focus"`   // 0-10: orientación a desarrollo
+	Privacy     int    `json:"privacy"`     // 0-10: enfoque en privacidad/seguridad
+	Pentest     int    `json:"pentest"`     // 0-10: herramientas de pentesting
+	Lightweight int    `json:"lightweight"` // 0-10: apta para hardware viejo/limitado
+	Popularity  int    `json:"popularity"`  // HPD (Hits Per Day) de DistroWatch
+	Trend       Trend  `json:"trend"`
+
+	// Atributos categóricos, usados por las restricciones duras (Constraints).
+	BaseFamily     []string `json:"base_family"`     // linaje, incluida la propia: ["ubuntu", "debian"]
+	Init           []string `json:"init"`            // sistemas de init disponibles
+	PackageManager []string `json:"package_manager"` // gestores de paquetes nativos
+	Architectures  []string `json:"architectures"`   // arquitecturas con imagen oficial
+	ReleaseModel   []string `json:"release_model"`   // "rolling", "fixed" y/o "lts"
 }
 
 // Trend representa la tendencia de popularidad.
@@ -21,3 +33,31 @@ const (
 	TrendStable Trend = 0
 	TrendUp   Trend = 1
 )
+
+// MarshalText serializa la tendencia como "up", "stable" o "down".
+func (t Trend) MarshalText() ([]byte, error) {
+	switch t {
+	case TrendUp:
+		return []byte("up"), nil
+	case TrendDown:
+		return []byte("down"), nil
+	case TrendStable:
+		return []byte("stable"), nil
+	}
+	return nil, fmt.Errorf("invalid trend %d", int(t))
+}
+
+// UnmarshalText interpreta "up", "stable" o "down".
+func (t *Trend) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "up":
+		*t = TrendUp
+	case "down":
+		*t = TrendDown
+	case "stable":
+		*t = TrendStable
+	default:
+		return fmt.Errorf("invalid trend %q (expected up, stable or down)", text)
+	}
+	return nil
+}
@@ -5,288 +5,273 @@
 package score
 
 import (
-	"log"
+	"fmt"
 	"math"
-	"strings"
+	"slices"
+	"sort"
+	"sync"
 
 	"distroanalyzer/profile"
 )
 
 // Engine calcula el puntaje final aplicando reglas determinísticas.
 type Engine struct {
-	distros []Distro
+	// mu protege catalog y presets, que pueden reemplazarse en caliente (SIGHUP).
+	mu      sync.RWMutex
+	catalog *Catalog
+	presets *Presets
 }
 
 type ScoreOutput struct {
 	Result         *profile.Result
 	BestDistroID   string
 	BestDistroName string
-}
 
+	// Ranking contiene las mejores distros en orden, empezando por la principal.
+	Ranking []profile.RankedDistro
 
+	// Trace documenta dimensiones, candidatas y reglas aplicadas.
+	Trace *profile.ScoreTrace
 
-// NewEngine crea un motor de scoring con la base de distros.
-func NewEngine(distros []Distro) *Engine {
-	return &Engine{
-		distros: distros,
-	}
+	// Version y CatalogVersion identifican las reglas y el catálogo que se
+	// usaron en este cálculo, aunque se hayan recargado mientras tanto.
+	Version        string
+	CatalogVersion string
 }
 
-// Score calcula el resultado final para un perfil basado en sus señales.
-func (e *Engine) Score(signals *profile.Signals) *ScoreOutput {
-	// Calcular dimensiones del usuario
-	dimensions := e.calculateDimensions(signals)
-
-	// DEBUG: Ver dimensiones calculadas
-	log.Printf("DEBUG - User dimensions: Rolling=%d, DIY=%d, Perf=%d, Dev=%d",
-		   dimensions.RollingScore, dimensions.DIYScore,
-	    dimensions.PerformanceScore, dimensions.DevScore)
-
+// Options ajusta un cálculo puntual sin modificar el motor.
+type Options struct {
+	// Preset es el nombre de la configuración a usar. Vacío = "default".
+	Preset string
 
-	// Encontrar mejor match
-	bestMatch := e.findBestMatch(dimensions, signals)
+	// TopN es la cantidad de distros del ranking. 0 = DefaultTopN.
+	TopN int
 
-	log.Printf("DEBUG - Best match: %s (score: %.2f)",
-		   bestMatch.distro.Name, bestMatch.matchScore)
-
-	// Calcular score final (0-100)
-	finalScore := e.calculateFinalScore(bestMatch, dimensions, signals)
-
-	// Determinar categoría
-	category := e.determineCategory(finalScore)
-
-	// Generar explicación
-	explanation := e.buildExplanation(bestMatch, dimensions, finalScore)
-
-	return &ScoreOutput{
-		Result: &profile.Result{
-			Score:       finalScore,
-			Category:    category,
-			Explanation: explanation,
-			Confidence:  bestMatch.matchScore,
-		},
-		BestDistroID:   bestMatch.distro.ID,
-		BestDistroName: bestMatch.distro.Name,
-}
+	// Constraints descarta distros antes del ranking.
+	Constraints Constraints
 }
 
-// calculateDimensions extrae dimensiones del perfil a partir de señales.
-func (e *Engine) calculateDimensions(signals *profile.Signals) UserDimensions {
-	dims := UserDimensions{}
+// DefaultTopN es la cantidad de distros que Score incluye en el ranking.
+const DefaultTopN = 3
 
-	// 1. Ciclo de vida (Rolling vs LTS)
-	dims.RollingScore = calculateRollingPreference(signals)
+// NewEngine crea un motor de scoring con el catálogo de distros y los presets.
+func NewEngine(catalog *Catalog, presets *Presets) *Engine {
+	return &Engine{
+		catalog: catalog,
+		presets: presets,
+	}
+}
 
-	// 2. Personalización (DIY vs Easy)
-	dims.DIYScore = calculateDIYPreference(signals)
+// Catalog devuelve el catálogo en uso.
+func (e *Engine) Catalog() *Catalog {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.catalog
+}
 
-	// 3. Performance/Gaming
-	dims.PerformanceScore = calculatePerformanceNeed(signals)
+// SetCatalog reemplaza el catálogo en uso sin reiniciar el motor.
+// Los cálculos en curso terminan con el catálogo anterior.
+func (e *Engine) SetCatalog(catalog *Catalog) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.catalog = catalog
+}
 
-	// 4. Developer focus
-	dims.DevScore = calculateDevFocus(signals)
+// Presets devuelve los presets en uso.
+func (e *Engine) Presets() *Presets {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.presets
+}
 
-	return dims
+// SetPresets reemplaza los presets en uso sin reiniciar el motor.
+func (e *Engine) SetPresets(presets *Presets) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.presets = presets
 }
 
-// calculateRollingPreference detecta preferencia por rolling/bleeding edge.
-func calculateRollingPreference(signals *profile.Signals) int {
-	score := 5 // neutral
+// EngineVersion es la versión del código de scoring. No se deriva de nada:
+// hay que subirla a mano en cada cambio del motor que altere resultados.
+// Los pesos ya se reflejan en el hash del preset, y el catálogo y la
+// taxonomía tienen su propia versión en profile.Versions.
+const EngineVersion = "1"
 
-	// Tecnologías bleeding edge
-	bleedingTech := []string{"rust", "mojo", "zig", "deno", "bun"}
-	for _, tech := range signals.TechStack {
-		for _, bleeding := range bleedingTech {
-			if tech == bleeding {
-				score += 2
-			}
-		}
+// Version identifica las reglas con que Score calcula para preset:
+// EngineVersion más el hash de su configuración. Vacío si el preset no
+// existe.
+func (e *Engine) Version(preset string) string {
+	if preset == "" {
+		preset = DefaultPreset
 	}
-
-	// Experience senior = más tolerancia al cambio
-	if signals.ExperienceLevel == profile.ExpSenior {
-		score += 1
-	}
-
-	// Keywords de estabilidad
-	stableKeywords := []string{"production", "enterprise", "stable", "lts"}
-	for _, kw := range signals.Keywords {
-		for _, stable := range stableKeywords {
-			if kw == stable {
-				score -= 2
-			}
-		}
+	cfg, ok := e.Presets().Get(preset)
+	if !ok {
+		return ""
 	}
-
-	return clamp(score, 0, 10)
+	return EngineVersion + "-" + cfg.Hash()
 }
 
-// calculateDIYPreference detecta si prefiere customización o simplicidad.
-func calculateDIYPreference(signals *profile.Signals) int {
-	score := 5 // neutral
-
-	// Keywords clave
-	diyKeywords := []string{
-		"dotfiles", "rice", "customization", "tiling", "window manager",
-		"kernel", "arch", "gentoo", "nixos", "low-level", "assembly",
-		"hyprland", "sway", "i3", "awesome", "dwm", "qtile", "bspwm", // tiling WMs top
-		"wayland", "x11", "compositor", "eww", "polybar", "waybar", "rofi", "wofi", // bars y launchers
-		"minimal", "minimalism", "void", "artix", "crux", "alpine", "kiss", // ultra-minimal
-		"lfs", "linux from scratch", "custom kernel", "musl", "glibc hardening",
-		"immutable", "atomic", "silverblue", "kinoite", "bazzite", "ublue", // atomic desktops
-		"home-manager", "flakes", "nix", "guix", // declarative config
-		"ricing", "unixporn", "gruvbox", "catppuccin", "tokyonight", // themes populares
-	}
-
-	easyKeywords := []string{"beginner", "simple", "easy", "user-friendly"}
-
-	for _, kw := range signals.Keywords {
-		for _, diy := range diyKeywords {
-			if kw == diy {
-				score += 3
-			}
-		}
-		for _, easy := range easyKeywords {
-			if kw == easy {
-				score -= 2
-			}
-		}
+// Score calcula el resultado final para un perfil basado en sus señales.
+// La primera posición del ranking es siempre la recomendación principal.
+// Si las restricciones descartan todas las distros devuelve *NoCandidatesError.
+func (e *Engine) Score(signals *profile.Signals, opts Options) (*ScoreOutput, error) {
+	n := opts.TopN
+	if n < 1 {
+		n = DefaultTopN
 	}
 
-	// Tech stack de scripting
-	scriptingLangs := []string{"bash", "lua", "python"}
-	scriptCount := 0
-	for _, tech := range signals.TechStack {
-		for _, script := range scriptingLangs {
-			if tech == script {
-				scriptCount++
-			}
-		}
-	}
-	if scriptCount >= 2 {
-		score += 2
+	presetName := opts.Preset
+	if presetName == "" {
+		presetName = DefaultPreset
 	}
 
-	return clamp(score, 0, 10)
-}
-
-// calculatePerformanceNeed detecta necesidad de alto rendimiento/gaming.
-func calculatePerformanceNeed(signals *profile.Signals) int {
-	score := 3 // bajo por defecto
-
-	perfKeywords := []string{"gaming", "performance", "gpu", "vulkan", "shader", "godot", "unreal"}
-
-	perfTech := []string{
-		"c", "c++", "rust", "vulkan", "opengl", "gpu",
-		"cuda", "rocm", "opencl", "metal", // compute/GPGPU
-		"directx", "dx12", "webgpu", // si menciona ports o gamedev
-		"assembly", "asm", "x86", "arm", "riscv", // low-level
-		"hpc", "mpi", "openmp", "simd", "avx", "avx512",
-		"zig", "c++20", "c++23", "cpp", // modern perf langs
-		"ispc", "halide", // domain-specific perf langs
-		"game dev", "godot", "unreal", "unity", // engines que piden perf
+	cfg, ok := e.Presets().Get(presetName)
+	if !ok {
+		return nil, &UnknownPresetError{Name: presetName}
 	}
+	configHash := cfg.Hash()
 
-	for _, kw := range signals.Keywords {
-		for _, perf := range perfKeywords {
-			if kw == perf {
-				score += 2
-			}
+	// Calcular dimensiones del usuario
+	dimensions := calculateDimensions(cfg, signals)
+
+	trace := &profile.ScoreTrace{
+		Preset:      presetName,
+		ConfigHash:  configHash,
+		Dimensions:  dimensions.Map(),
+		Constraints: opts.Constraints.Terms(),
+	}
+
+	// Aplicar restricciones duras antes de ordenar
+	catalog := e.Catalog()
+	candidates, excluded, err := filterCandidates(opts.Constraints, catalog.Distros)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ordenar candidatas por fit
+	matches, skipped := rankMatches(cfg, catalog, candidates, dimensions, signals)
+	skipped = append(skipped, excluded...)
+	if len(matches) == 0 {
+		trace.Candidates = skipped
+		return &ScoreOutput{
+			Result: &profile.Result{
+				Score:      0,
+				Category:   profile.FitNone,
+				Preset:     presetName,
+				ConfigHash: configHash,
+			},
+			Trace:          trace,
+			Version:        EngineVersion + "-" + configHash,
+			CatalogVersion: catalog.Version,
+		}, nil
+	}
+
+	// Calcular score final (0-100) de cada candidata y ordenar por él: es
+	// el que se muestra, y los ajustes pueden cambiar qué distro gana.
+	// Ante empate decide el match score.
+	for i := range matches {
+		matches[i].trace.FinalScore, matches[i].trace.Adjustments =
+			calculateFinalScore(cfg, matches[i], dimensions, signals)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].trace.FinalScore != matches[j].trace.FinalScore {
+			return matches[i].trace.FinalScore > matches[j].trace.FinalScore
 		}
-	}
+		return matches[i].matchScore > matches[j].matchScore
+	})
 
-	for _, tech := range signals.TechStack {
-		for _, perf := range perfTech {
-			if tech == perf {
-				score += 1
-			}
+	ranking := make([]profile.RankedDistro, 0, n)
+	for i := range matches {
+		if i >= n {
+			trace.Candidates = append(trace.Candidates, matches[i].trace)
+			continue
 		}
-	}
+		match := matches[i]
+		finalScore := match.trace.FinalScore
 
-	return clamp(score, 0, 10)
-}
-
-// calculateDevFocus detecta orientación a desarrollo/DevOps.
-func calculateDevFocus(signals *profile.Signals) int {
-	score := 5
+		match.trace.Rank = i + 1
+		trace.Candidates = append(trace.Candidates, match.trace)
 
-	devTech := []string{
-		"c", "c++", "go", "rust", "python", "ruby", "javascript", "typescript",
-		"java", "kotlin", "swift", "php", "perl", "shell", "bash", "lua",
-		"docker", "kubernetes", "terraform", "ansible", "vagrant", "chef", "puppet",
-		"jenkins", "gitlab", "github actions", "circleci",
-		"aws", "gcp", "azure", "cloud",
-		"git", "make", "cmake", "gradle", "maven", "npm", "yarn", "pip",
+		ranking = append(ranking, profile.RankedDistro{
+			DistroID:    match.distro.ID,
+			DistroName:  match.distro.Name,
+			MatchScore:  match.matchScore,
+			Score:       finalScore,
+			Category:    determineCategory(cfg, finalScore),
+			Explanation: buildExplanation(match, dimensions, finalScore),
+		})
 	}
 
-	devKeywords := []string{
-		"devops", "backend", "infrastructure", "sre", "platform",
-		"rails", "web", "api", "microservices", "containers", "orchestration",
-		"automation", "ci/cd", "deployment", "ansible", "kubernetes", "k8s",
-	}
+	trace.Candidates = append(trace.Candidates, skipped...)
 
-	// Bonus especial por keywords de alto nivel
-	criticalKeywords := []string{"kernel", "ansible", "kubernetes", "k8s", "docker", "devops"}
+	best := ranking[0]
 
-	for _, kw := range signals.Keywords {
-		kwLower := strings.ToLower(kw)
-		for _, critical := range criticalKeywords {
-			if strings.Contains(kwLower, critical) {
-				score += 2  // Bonus grande
-			}
-		}
-	}
-
-	for _, tech := range signals.TechStack {
-		techLower := strings.ToLower(tech)
-		for _, dev := range devTech {
-			if techLower == dev || strings.Contains(techLower, dev) {
-				score += 1
-			}
-		}
-	}
+	return &ScoreOutput{
+		Result: &profile.Result{
+			Score:       best.Score,
+			Category:    best.Category,
+			Explanation: best.Explanation,
+			Confidence:  best.MatchScore,
+			Preset:      presetName,
+			ConfigHash:  configHash,
+		},
+		BestDistroID:   best.DistroID,
+		BestDistroName: best.DistroName,
+		Ranking:        ranking,
+		Trace:          trace,
+		Version:        EngineVersion + "-" + configHash,
+		CatalogVersion: catalog.Version,
+	}, nil
+}
 
-	for _, kw := range signals.Keywords {
-		kwLower := strings.ToLower(kw)
-		for _, dev := range devKeywords {
-			if kwLower == dev || strings.Contains(kwLower, dev) {
-				score += 1
-			}
-		}
-	}
+// UnknownPresetError indica que se pidió un preset que no existe.
+type UnknownPresetError struct {
+	Name string
+}
 
-	return clamp(score, 0, 10)
+func (e *UnknownPresetError) Error() string {
+	return fmt.Sprintf("unknown scoring preset %q", e.Name)
 }
 
-// findBestMatch encuentra la distro con mejor fit.
-func (e *Engine) findBestMatch(dims UserDimensions, signals *profile.Signals) MatchResult {
-	var best MatchResult
-	bestScore := 0.0
+// rankMatches devuelve las candidatas ordenadas de mejor a peor fit, junto
+// con la traza de las que se descartaron antes de ordenar. La popularidad se
+// normaliza contra todo el catálogo, no solo contra las candidatas.
+func rankMatches(cfg *EngineConfig, catalog *Catalog, candidates []Distro, dims UserDimensions, signals *profile.Signals) ([]MatchResult, []profile.CandidateTrace) {
+	matches := make([]MatchResult, 0, len(candidates))
+	var skipped []profile.CandidateTrace
 
-	// máximo teórico de distancia euclidiana en este espacio:
-	// cada dimensión 0..10, 4 dimensiones => maxDist = sqrt(4 * 10^2) = 20
-	const maxDist = 20.0
+	// máximo teórico de distancia euclidiana en este espacio
+	maxDist := maxDistance()
 
-	// parámetros (tuneables)
-	const alpha = 0.90 // peso para la similitud geométrica
-	const beta = 0.10  // peso para la popularidad
+	alpha := cfg.Match.Alpha // peso para la similitud geométrica
+	beta := cfg.Match.Beta   // peso para la popularidad
 
-	// precomputar maxPopularity
-	maxPopularity := 3790.0
+	// la popularidad se normaliza contra la distro más popular del catálogo
+	maxPopularity := float64(catalog.MaxPopularity())
 
-	for _, distro := range e.distros {
-		// Skip distros muy oscuras para usuarios con perfil claro
-		if signals.ExperienceLevel == profile.ExpSenior && distro.Popularity < 500 {
+	// Skip distros muy oscuras para usuarios con perfil claro, salvo que
+	// las restricciones solo dejen distros así: mejor una de nicho que
+	// ninguna recomendación
+	minPopularity := 0
+	if signals.ExperienceLevel == profile.ExpSenior {
+		minPopularity = cfg.Match.SeniorMinPopularity
+	}
+	if !slices.ContainsFunc(candidates, func(d Distro) bool { return d.Popularity >= minPopularity }) {
+		minPopularity = 0
+	}
+
+	for _, distro := range candidates {
+		if distro.Popularity < minPopularity {
+			skipped = append(skipped, profile.CandidateTrace{
+				DistroID:   distro.ID,
+				DistroName: distro.Name,
+				SkipReason: "senior_min_popularity",
+			})
 			continue
 		}
 
 		// distancia euclidiana simple entre dimensiones
-		distance := math.Sqrt(
-			math.Pow(float64(dims.RollingScore-distro.Rolling), 2) +
-			math.Pow(float64(dims.DIYScore-distro.DIY), 2) +
-			math.Pow(float64(dims.PerformanceScore-distro.Performance), 2) +
-			math.Pow(float64(dims.DevScore-distro.DevFocus), 2),
-		)
+		distance := dims.distanceTo(distro)
 
 		// Normalizar distancia y convertir a similitud [0..1]
 		normDist := distance / maxDist
@@ -313,124 +298,146 @@ func (e *Engine) findBestMatch(dims UserDimensions, signals *profile.Signals) Ma
 		// pequeña corrección por tendencia
 		trendMultiplier := 1.0
 		switch distro.Trend {
-			case TrendUp:
-				trendMultiplier = 1.08 // +8%
-			case TrendDown:
-				trendMultiplier = 0.97 // -3%
+		case TrendUp:
+			trendMultiplier = cfg.Match.TrendUpMultiplier
+		case TrendDown:
+			trendMultiplier = cfg.Match.TrendDownMultiplier
 		}
 
 		// combinar: suma ponderada
 		finalScore := (alpha*similarity + beta*popNorm) * trendMultiplier
 
-		if finalScore > bestScore {
-			bestScore = finalScore
-			best = MatchResult{
-				distro:     distro,
-				matchScore: finalScore,
-			}
-		}
+		matches = append(matches, MatchResult{
+			distro:     distro,
+			matchScore: finalScore,
+			trace: profile.CandidateTrace{
+				DistroID:        distro.ID,
+				DistroName:      distro.Name,
+				Distance:        distance,
+				Similarity:      similarity,
+				PopularityTerm:  popNorm,
+				TrendMultiplier: trendMultiplier,
+			},
+		})
 	}
 
-	// Después del loop principal, antes de return best:
+	// Orden estable: ante empate gana la que aparece primero en la base.
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].matchScore > matches[j].matchScore
+	})
+
+	// Las penalizaciones bajan el match score; el orden definitivo lo da
+	// el score final, en Score.
 
 	// Penalizar distros genéricas para usuarios senior avanzados
 	if signals.ExperienceLevel == profile.ExpSenior {
-		// Si DevScore es alto pero la distro tiene DevFocus bajo, penalizar
-		if dims.DevScore >= 8 && best.distro.DevFocus <= 7 {
-			best.matchScore *= 0.85  // -15% penalty
-			log.Printf("DEBUG - Penalizing generic distro %s for senior dev profile", best.distro.Name)
-		}
+		p := cfg.Penalties
+		for i := range matches {
+			m := &matches[i]
+
+			// Si DevScore es alto pero la distro tiene DevFocus bajo, penalizar
+			if dims.DevScore >= p.SeniorDevMinScore && m.distro.DevFocus <= p.SeniorDevMaxDevFocus {
+				m.applyPenalty("senior_dev", p.SeniorDevFactor)
+			}
 
-		// Si DIY alto pero distro es muy "easy", penalizar
-		if dims.DIYScore >= 8 && best.distro.Easy >= 9 {
-			best.matchScore *= 0.90  // -10% penalty
-			log.Printf("DEBUG - Penalizing too-easy distro %s for DIY user", best.distro.Name)
+			// Si DIY alto pero distro es muy "easy", penalizar
+			if dims.DIYScore >= p.SeniorDIYMinScore && m.distro.Easy >= p.SeniorDIYMinEasy {
+				m.applyPenalty("senior_diy", p.SeniorDIYFactor)
+			}
 		}
 	}
 
-	return best
-}
+	for i := range matches {
+		matches[i].trace.MatchScore = matches[i].matchScore
+	}
 
+	return matches, skipped
+}
 
 // calculateFinalScore convierte el match score a escala 0-100.
-func (e *Engine) calculateFinalScore(match MatchResult, dims UserDimensions, signals *profile.Signals) int {
+// También devuelve cada regla de ajuste que se disparó, con su delta.
+func calculateFinalScore(cfg *EngineConfig, match MatchResult, dims UserDimensions, signals *profile.Signals) (int, []profile.AdjustmentTrace) {
 	// baseScore en 0..100
 	baseScore := int(math.Round(match.matchScore * 100.0))
 
 	adjustment := 0
+	adj := cfg.Adjustments
+
+	var fired []profile.AdjustmentTrace
+	apply := func(rule string, delta int) {
+		adjustment += delta
+		fired = append(fired, profile.AdjustmentTrace{Rule: rule, Delta: delta})
+	}
 
 	// 1. Ajuste por nivel de experiencia vs facilidad de uso
 	distro := match.distro
 
 	if signals.ExperienceLevel == profile.ExpJunior {
 		// Usuarios junior: bonus por distros fáciles
-		if distro.Easy >= 8 {
-			adjustment += 5
+		if distro.Easy >= adj.JuniorEasyMin {
+			apply("junior_easy", adj.JuniorEasyDelta)
 		}
 		// Penalización por distros DIY extremas
-		if distro.DIY >= 9 {
-			adjustment -= 10
+		if distro.DIY >= adj.JuniorDIYMin {
+			apply("junior_diy", adj.JuniorDIYDelta)
 		}
 	} else if signals.ExperienceLevel == profile.ExpSenior {
 		// Usuarios senior: bonus por distros con alto DevFocus
-		if distro.DevFocus >= 9 {
-			adjustment += 5
+		if distro.DevFocus >= adj.SeniorDevFocusMin {
+			apply("senior_dev_focus", adj.SeniorDevFocusDelta)
 		}
 		// Bonus menor por DIY (aprecian el control)
-		if distro.DIY >= 7 {
-			adjustment += 3
+		if distro.DIY >= adj.SeniorDIYMin {
+			apply("senior_diy", adj.SeniorDIYDelta)
 		}
 		// Ligera penalización por distros demasiado simples
-		if distro.Easy >= 10 && distro.DIY <= 2 {
-			adjustment -= 3
+		if distro.Easy >= adj.SeniorTooEasyMin && distro.DIY <= adj.SeniorTooEasyMaxDIY {
+			apply("senior_too_easy", adj.SeniorTooEasyDelta)
 		}
 	}
 
 	// 2. Ajuste por coherencia de dimensiones
 	// Si el usuario tiene alto DevFocus pero la distro tiene bajo, penalizar
-	if dims.DevScore >= 8 && distro.DevFocus <= 5 {
-		adjustment -= 5
+	if dims.DevScore >= adj.DevMismatchUserMin && distro.DevFocus <= adj.DevMismatchDistroMax {
+		apply("dev_mismatch", adj.DevMismatchDelta)
 	}
 
 	// Si el usuario necesita performance pero la distro es débil, penalizar
-	if dims.PerformanceScore >= 8 && distro.Performance <= 5 {
-		adjustment -= 5
+	if dims.PerformanceScore >= adj.PerfMismatchUserMin && distro.Performance <= adj.PerfMismatchDistroMax {
+		apply("perf_mismatch", adj.PerfMismatchDelta)
 	}
 
 	// 3. Bonus por match perfecto en múltiples dimensiones
+	tolerance := adj.PerfectMatchTolerance
 	perfectMatches := 0
-	if abs(dims.RollingScore - distro.Rolling) <= 1 {
-		perfectMatches++
-	}
-	if abs(dims.DIYScore - distro.DIY) <= 1 {
-		perfectMatches++
-	}
-	if abs(dims.PerformanceScore - distro.Performance) <= 1 {
-		perfectMatches++
-	}
-	if abs(dims.DevScore - distro.DevFocus) <= 1 {
-		perfectMatches++
+	for _, dim := range dimensions {
+		if dim.base != nil && dim.user(dims) <= dim.base(cfg) {
+			continue
+		}
+		if abs(dim.user(dims)-dim.distro(distro)) <= tolerance {
+			perfectMatches++
+		}
 	}
 
 	// Bonus progresivo por matches múltiples
 	if perfectMatches >= 3 {
-		adjustment += 8
+		apply("perfect_match_3", adj.PerfectMatch3Delta)
 	} else if perfectMatches == 2 {
-		adjustment += 4
+		apply("perfect_match_2", adj.PerfectMatch2Delta)
 	}
 
 	// 4. Ajuste por popularidad extrema (evitar distros muy oscuras o moribundas)
-	if distro.Popularity < 150 {
-		adjustment -= 3 // Distros muy nicho
+	if distro.Popularity < adj.NichePopularityBelow {
+		apply("niche", adj.NicheDelta) // Distros muy nicho
 	}
 
-	if distro.Trend == TrendDown && distro.Popularity < 300 {
-		adjustment -= 5 // Distro en declive y poco popular = riesgoso
+	if distro.Trend == TrendDown && distro.Popularity < adj.DecliningPopularityBelow {
+		apply("declining", adj.DecliningDelta) // Distro en declive y poco popular = riesgoso
 	}
 
 	finalScore := baseScore + adjustment
 
-	return clamp(finalScore, 0, 100)
+	return clamp(finalScore, 0, 100), fired
 }
 
 // Helper: valor absoluto
@@ -440,19 +447,20 @@ func abs(x int) int {
 	}
 	return x
 }
+
 // determineCategory asigna categoría cualitativa.
-func (e *Engine) determineCategory(score int) profile.FitCategory {
-	if score >= 75 {
+func determineCategory(cfg *EngineConfig, score int) profile.FitCategory {
+	if score >= cfg.Categories.StrongMin {
 		return profile.FitStrong
 	}
-	if score >= 50 {
+	if score >= cfg.Categories.PotentialMin {
 		return profile.FitPotential
 	}
 	return profile.FitNone
 }
 
 // buildExplanation genera texto legible del resultado.
-func (e *Engine) buildExplanation(match MatchResult, dims UserDimensions, score int) string {
+func buildExplanation(match MatchResult, dims UserDimensions, score int) string {
 	distro := match.distro
 
 	explanation := "Basado en tu perfil, recomendamos " + distro.Name + ". "
@@ -467,6 +475,15 @@ func (e *Engine) buildExplanation(match MatchResult, dims UserDimensions, score
 	if dims.PerformanceScore >= 7 && distro.Performance >= 8 {
 		explanation += "Necesitas alto rendimiento y " + distro.Name + " está optimizada para ello. "
 	}
+	if dims.PrivacyScore >= 7 && distro.Privacy >= 8 {
+		explanation += "Te importa la privacidad y " + distro.Name + " está diseñada con ese foco. "
+	}
+	if dims.PentestScore >= 7 && distro.Pentest >= 8 {
+		explanation += distro.Name + " trae de serie las herramientas de pentesting que usas. "
+	}
+	if dims.LowSpecScore >= 7 && distro.Lightweight >= 8 {
+		explanation += distro.Name + " es liviana y funciona bien en hardware modesto. "
+	}
 
 	// Popularidad y tendencia
 	if distro.Trend == TrendUp {
@@ -487,16 +504,15 @@ func clamp(value, min, max int) int {
 	return value
 }
 
-// UserDimensions representa las dimensiones calculadas del usuario.
-type UserDimensions struct {
-	RollingScore     int // 0-10: LTS(0) → Rolling(10)
-	DIYScore         int // 0-10: Easy(0) → DIY(10)
-	PerformanceScore int // 0-10: necesidad de rendimiento
-	DevScore         int // 0-10: orientación desarrollo
-}
-
 // MatchResult representa el resultado del matching.
 type MatchResult struct {
 	distro     Distro
 	matchScore float64 // 0.0-1.0
+	trace      profile.CandidateTrace
+}
+
+// applyPenalty multiplica el match score por factor y lo registra en la traza.
+func (m *MatchResult) applyPenalty(rule string, factor float64) {
+	m.matchScore *= factor
+	m.trace.Penalties = append(m.trace.Penalties, profile.PenaltyTrace{Rule: rule, Factor: factor})
 }
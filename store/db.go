@@ -12,12 +12,14 @@ import (
 	"distroanalyzer/profile"
 )
 
-// Store representa un almacén persistente de perfiles.
+// Store representa un almacén persistente de perfiles. Un perfil se
+// identifica por fuente y username: "alice" en GitHub y en GitLab son
+// perfiles distintos.
 type Store interface {
 	Save(ctx context.Context, p *profile.Profile) error
-	GetByUsername(ctx context.Context, username string) (*profile.Profile, error)
+	Get(ctx context.Context, source, username string) (*profile.Profile, error)
 	List(ctx context.Context, limit, offset int) ([]*profile.Profile, error)
-	Delete(ctx context.Context, username string) error
+	Delete(ctx context.Context, source, username string) error
 }
 
 // SQLiteStore implementa Store usando SQLite.
@@ -51,21 +53,115 @@ func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
 func (s *SQLiteStore) migrate() error {
 	query := `
 	CREATE TABLE IF NOT EXISTS profiles (
-		username TEXT PRIMARY KEY,
+		username TEXT NOT NULL,
 		source TEXT NOT NULL,
 		raw_data TEXT,
 		signals TEXT NOT NULL,
 		result TEXT NOT NULL,
 		created_at DATETIME NOT NULL,
-		updated_at DATETIME NOT NULL
+		updated_at DATETIME NOT NULL,
+		PRIMARY KEY (source, username)
 		);
 
 		CREATE INDEX IF NOT EXISTS idx_created_at ON profiles(created_at DESC);
 		CREATE INDEX IF NOT EXISTS idx_source ON profiles(source);
 		`
 
-		_, err := s.db.Exec(query)
+		if _, err := s.db.Exec(query); err != nil {
+			return err
+		}
+
+		// Columnas agregadas después de la versión inicial
+		if err := s.addColumnIfMissing("recommendation", "TEXT"); err != nil {
+			return err
+		}
+		if err := s.addColumnIfMissing("trace", "TEXT"); err != nil {
+			return err
+		}
+		if err := s.addColumnIfMissing("versions", "TEXT"); err != nil {
+			return err
+		}
+		return s.migrateSourceKey()
+}
+
+// migrateSourceKey reconstruye la tabla de versiones anteriores, cuya
+// clave primaria era solo username. SQLite no permite cambiar la clave
+// primaria con ALTER TABLE.
+func (s *SQLiteStore) migrateSourceKey() error {
+	var sourceInKey bool
+	err := s.db.QueryRow(`SELECT pk > 0 FROM pragma_table_info('profiles') WHERE name = 'source'`).Scan(&sourceInKey)
+	if err != nil {
+		return err
+	}
+	if sourceInKey {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	steps := []string{
+		`CREATE TABLE profiles_new (
+			username TEXT NOT NULL,
+			source TEXT NOT NULL,
+			raw_data TEXT,
+			signals TEXT NOT NULL,
+			result TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			recommendation TEXT,
+			trace TEXT,
+			versions TEXT,
+			PRIMARY KEY (source, username)
+		)`,
+		`INSERT INTO profiles_new (username, source, raw_data, signals, result, created_at, updated_at, recommendation, trace, versions)
+		SELECT username, source, raw_data, signals, result, created_at, updated_at, recommendation, trace, versions FROM profiles`,
+		`DROP TABLE profiles`,
+		`ALTER TABLE profiles_new RENAME TO profiles`,
+		`CREATE INDEX IF NOT EXISTS idx_created_at ON profiles(created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_source ON profiles(source)`,
+	}
+	for _, step := range steps {
+		if _, err := tx.Exec(step); err != nil {
+			return fmt.Errorf("rebuild profiles table: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// addColumnIfMissing agrega una columna a profiles si todavía no existe.
+func (s *SQLiteStore) addColumnIfMissing(name, definition string) error {
+	rows, err := s.db.Query(`PRAGMA table_info(profiles)`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			colName    string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &colName, &colType, &notNull, &defaultVal, &pk); err != nil {
+			return err
+		}
+		if colName == name {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
 		return err
+	}
+
+	_, err = s.db.Exec(fmt.Sprintf("ALTER TABLE profiles ADD COLUMN %s %s", name, definition))
+	return err
 }
 
 // Save guarda o actualiza un perfil.
@@ -86,16 +182,39 @@ func (s *SQLiteStore) Save(ctx context.Context, p *profile.Profile) error {
 			return fmt.Errorf("failed to marshal result: %w", err)
 		}
 
+		recommendationJSON, err := json.Marshal(p.Recommendation)
+		if err != nil {
+			return fmt.Errorf("failed to marshal recommendation: %w", err)
+		}
+
+		// La traza es opcional: NULL si el perfil no la tiene
+		var traceJSON sql.NullString
+		if p.Trace != nil {
+			data, err := json.Marshal(p.Trace)
+			if err != nil {
+				return fmt.Errorf("failed to marshal trace: %w", err)
+			}
+			traceJSON = sql.NullString{String: string(data), Valid: true}
+		}
+
+		versionsJSON, err := json.Marshal(p.Versions)
+		if err != nil {
+			return fmt.Errorf("failed to marshal versions: %w", err)
+		}
+
 		now := time.Now()
 
 		query := `
-		INSERT INTO profiles (username, source, raw_data, signals, result, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(username) DO UPDATE SET
+		INSERT INTO profiles (username, source, raw_data, signals, result, recommendation, trace, versions, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(source, username) DO UPDATE SET
 		source = excluded.source,
 		raw_data = excluded.raw_data,
 		signals = excluded.signals,
 		result = excluded.result,
+		recommendation = excluded.recommendation,
+		trace = excluded.trace,
+		versions = excluded.versions,
 		updated_at = excluded.updated_at
 		`
 
@@ -105,6 +224,9 @@ func (s *SQLiteStore) Save(ctx context.Context, p *profile.Profile) error {
 					  string(rawDataJSON),
 					  string(signalsJSON),
 					  string(resultJSON),
+					  string(recommendationJSON),
+					  traceJSON,
+					  string(versionsJSON),
 					  p.CreatedAt,
 					  now,
 		)
@@ -112,23 +234,27 @@ func (s *SQLiteStore) Save(ctx context.Context, p *profile.Profile) error {
 		return err
 }
 
-// GetByUsername obtiene un perfil por username.
-func (s *SQLiteStore) GetByUsername(ctx context.Context, username string) (*profile.Profile, error) {
+// Get obtiene un perfil por fuente y username.
+func (s *SQLiteStore) Get(ctx context.Context, source, username string) (*profile.Profile, error) {
 	query := `
-	SELECT username, source, raw_data, signals, result, created_at
+	SELECT username, source, raw_data, signals, result, recommendation, trace, versions, created_at
 	FROM profiles
-	WHERE username = ?
+	WHERE source = ? AND username = ?
 	`
 
 	var p profile.Profile
 	var rawDataJSON, signalsJSON, resultJSON string
+	var recommendationJSON, traceJSON, versionsJSON sql.NullString
 
-	err := s.db.QueryRowContext(ctx, query, username).Scan(
+	err := s.db.QueryRowContext(ctx, query, source, username).Scan(
 		&p.Username,
 		&p.Source,
 		&rawDataJSON,
 		&signalsJSON,
 		&resultJSON,
+		&recommendationJSON,
+		&traceJSON,
+		&versionsJSON,
 		&p.CreatedAt,
 	)
 
@@ -152,13 +278,34 @@ func (s *SQLiteStore) GetByUsername(ctx context.Context, username string) (*prof
 		return nil, fmt.Errorf("failed to unmarshal result: %w", err)
 	}
 
+	// Perfiles guardados antes de existir la columna no tienen recomendación
+	if recommendationJSON.Valid {
+		if err := json.Unmarshal([]byte(recommendationJSON.String), &p.Recommendation); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal recommendation: %w", err)
+		}
+	}
+
+	if traceJSON.Valid {
+		p.Trace = &profile.ScoreTrace{}
+		if err := json.Unmarshal([]byte(traceJSON.String), p.Trace); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal trace: %w", err)
+		}
+	}
+
+	// Perfiles anteriores a las versiones quedan con Versions vacío
+	if versionsJSON.Valid {
+		if err := json.Unmarshal([]byte(versionsJSON.String), &p.Versions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal versions: %w", err)
+		}
+	}
+
 	return &p, nil
 }
 
 // List obtiene perfiles paginados.
 func (s *SQLiteStore) List(ctx context.Context, limit, offset int) ([]*profile.Profile, error) {
 	query := `
-	SELECT username, source, raw_data, signals, result, created_at
+	SELECT username, source, raw_data, signals, result, recommendation, trace, versions, created_at
 	FROM profiles
 	ORDER BY created_at DESC
 	LIMIT ? OFFSET ?
@@ -175,6 +322,7 @@ func (s *SQLiteStore) List(ctx context.Context, limit, offset int) ([]*profile.P
 	for rows.Next() {
 		var p profile.Profile
 		var rawDataJSON, signalsJSON, resultJSON string
+		var recommendationJSON, traceJSON, versionsJSON sql.NullString
 
 		err := rows.Scan(
 			&p.Username,
@@ -182,6 +330,9 @@ func (s *SQLiteStore) List(ctx context.Context, limit, offset int) ([]*profile.P
 		   &rawDataJSON,
 		   &signalsJSON,
 		   &resultJSON,
+		   &recommendationJSON,
+		   &traceJSON,
+		   &versionsJSON,
 		   &p.CreatedAt,
 		)
 		if err != nil {
@@ -201,6 +352,25 @@ func (s *SQLiteStore) List(ctx context.Context, limit, offset int) ([]*profile.P
 			return nil, err
 		}
 
+		if recommendationJSON.Valid {
+			if err := json.Unmarshal([]byte(recommendationJSON.String), &p.Recommendation); err != nil {
+				return nil, err
+			}
+		}
+
+		if traceJSON.Valid {
+			p.Trace = &profile.ScoreTrace{}
+			if err := json.Unmarshal([]byte(traceJSON.String), p.Trace); err != nil {
+				return nil, err
+			}
+		}
+
+		if versionsJSON.Valid {
+			if err := json.Unmarshal([]byte(versionsJSON.String), &p.Versions); err != nil {
+				return nil, err
+			}
+		}
+
 		profiles = append(profiles, &p)
 	}
 
@@ -208,9 +378,9 @@ func (s *SQLiteStore) List(ctx context.Context, limit, offset int) ([]*profile.P
 }
 
 // Delete elimina un perfil.
-func (s *SQLiteStore) Delete(ctx context.Context, username string) error {
-	query := `DELETE FROM profiles WHERE username = ?`
-	_, err := s.db.ExecContext(ctx, query, username)
+func (s *SQLiteStore) Delete(ctx context.Context, source, username string) error {
+	query := `DELETE FROM profiles WHERE source = ? AND username = ?`
+	_, err := s.db.ExecContext(ctx, query, source, username)
 	return err
 }
 
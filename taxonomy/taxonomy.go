@@ -0,0 +1,381 @@
+// Package taxonomy normaliza tecnologías y keywords a términos canónicos.
+//
+// Una sola tabla define alias ("k8s" → "kubernetes"), jerarquía
+// ("hyprland" → "tiling-wm" → "window-manager" → "diy") y pesos. Tanto el
+// análisis de señales como el scoring la usan, así que un término se
+// comporta igual en todo el sistema.
+package taxonomy
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Term es una entrada canónica de la taxonomía.
+type Term struct {
+	// ID es la forma canónica, en minúsculas y con guiones.
+	ID string `json:"id"`
+
+	// Aliases son otras formas de escribir el mismo término.
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Parents son las categorías a las que pertenece el término.
+	Parents []string `json:"parents,omitempty"`
+
+	// Weight multiplica el aporte del término al scoring. 0 = 1.
+	Weight float64 `json:"weight,omitempty"`
+
+	// Exact indica que el término solo se reconoce como valor completo,
+	// no como parte de un nombre compuesto (ej: "atomic" en "atomic-counter").
+	Exact bool `json:"exact,omitempty"`
+}
+
+// Taxonomy es la tabla de términos indexada por ID y alias.
+type Taxonomy struct {
+	// Version identifica los datos: subirla al editar terms.json, porque
+	// invalida los análisis guardados.
+	Version string
+
+	terms   map[string]*Term
+	aliases map[string]string   // forma normalizada → ID
+	lineage map[string][]string // ID → ID + todos sus ancestros
+	maxLen  int                 // máxima cantidad de tokens de un alias
+}
+
+// taxonomyFile es el formato del archivo de datos.
+type taxonomyFile struct {
+	Version string `json:"version"`
+	Terms   []Term `json:"terms"`
+}
+
+//go:embed data/terms.json
+var defaultData []byte
+
+var (
+	defaultOnce sync.Once
+	defaultTax  *Taxonomy
+)
+
+// Default devuelve la taxonomía incluida en el binario.
+// Los datos embebidos se validan al compilar el paquete en uso, así que un
+// error acá es un bug del archivo de datos y no de la entrada del usuario.
+func Default() *Taxonomy {
+	defaultOnce.Do(func() {
+		tax, err := Parse(defaultData)
+		if err != nil {
+			panic(fmt.Sprintf("taxonomy: invalid embedded data: %v", err))
+		}
+		defaultTax = tax
+	})
+	return defaultTax
+}
+
+// Parse decodifica y valida una taxonomía en formato JSON.
+func Parse(data []byte) (*Taxonomy, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var file taxonomyFile
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid taxonomy JSON: %w", err)
+	}
+
+	t := &Taxonomy{
+		Version: file.Version,
+		terms:   make(map[string]*Term, len(file.Terms)),
+		aliases: make(map[string]string),
+		lineage: make(map[string][]string, len(file.Terms)),
+	}
+
+	var errs []error
+	for i := range file.Terms {
+		term := &file.Terms[i]
+		term.ID = Normalize(term.ID)
+		if term.ID == "" {
+			errs = append(errs, fmt.Errorf("terms[%d]: id is required", i))
+			continue
+		}
+		if _, dup := t.terms[term.ID]; dup {
+			errs = append(errs, fmt.Errorf("term %q: duplicate id", term.ID))
+			continue
+		}
+		if term.Weight == 0 {
+			term.Weight = 1
+		}
+		t.terms[term.ID] = term
+	}
+
+	for _, term := range t.terms {
+		for _, form := range append([]string{term.ID}, term.Aliases...) {
+			form = Normalize(form)
+			if other, dup := t.aliases[form]; dup && other != term.ID {
+				errs = append(errs, fmt.Errorf("alias %q used by %q and %q", form, other, term.ID))
+				continue
+			}
+			t.aliases[form] = term.ID
+			if n := len(tokenize(form)); n > t.maxLen {
+				t.maxLen = n
+			}
+		}
+		for i, parent := range term.Parents {
+			term.Parents[i] = Normalize(parent)
+			if _, ok := t.terms[term.Parents[i]]; !ok {
+				errs = append(errs, fmt.Errorf("term %q: unknown parent %q", term.ID, parent))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	for id := range t.terms {
+		lineage, err := t.buildLineage(id, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		t.lineage[id] = lineage
+	}
+
+	return t, nil
+}
+
+// buildLineage devuelve id y todos sus ancestros, detectando ciclos.
+func (t *Taxonomy) buildLineage(id string, visiting map[string]bool) ([]string, error) {
+	if visiting[id] {
+		return nil, fmt.Errorf("term %q: cycle in parents", id)
+	}
+	visiting[id] = true
+	defer delete(visiting, id)
+
+	lineage := []string{id}
+	seen := map[string]bool{id: true}
+	for _, parent := range t.terms[id].Parents {
+		ancestors, err := t.buildLineage(parent, visiting)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range ancestors {
+			if !seen[a] {
+				seen[a] = true
+				lineage = append(lineage, a)
+			}
+		}
+	}
+	return lineage, nil
+}
+
+// Normalize aplica case folding y unifica separadores:
+// "#Hyprland" → "hyprland", "Window Manager" → "window-manager".
+// Conserva los símbolos que distinguen tecnologías ("c++", "c#", "ci/cd").
+func Normalize(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimLeft(s, "#@")
+	s = strings.TrimRight(s, ".,;:!?")
+
+	var b strings.Builder
+	lastDash := false
+	for _, r := range s {
+		if unicode.IsSpace(r) || r == '_' || r == '-' {
+			if !lastDash && b.Len() > 0 {
+				b.WriteByte('-')
+				lastDash = true
+			}
+			continue
+		}
+		b.WriteRune(r)
+		lastDash = false
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// tokenize separa una forma normalizada en palabras.
+func tokenize(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == '-' || r == '.' || r == '/'
+	})
+}
+
+// Canonical devuelve el ID canónico de s si es un término o alias conocido.
+func (t *Taxonomy) Canonical(s string) (string, bool) {
+	id, ok := t.aliases[Normalize(s)]
+	return id, ok
+}
+
+// Resolve devuelve los términos canónicos presentes en s. Primero prueba el
+// valor completo; si no es conocido, busca términos dentro de nombres
+// compuestos ("kubernetes-operator" → "kubernetes").
+func (t *Taxonomy) Resolve(s string) []string {
+	norm := Normalize(s)
+	if id, ok := t.aliases[norm]; ok {
+		return []string{id}
+	}
+
+	var found []string
+	seen := map[string]bool{}
+	t.scan(tokenize(norm), func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			found = append(found, id)
+		}
+	})
+	return found
+}
+
+// ResolveText cuenta los términos mencionados en texto libre (bio, README).
+// Reconoce frases de varias palabras ("window manager"), pero los términos
+// Exact solo cuentan como hashtag ("#c"): sueltos en prosa son ambiguos.
+func (t *Taxonomy) ResolveText(s string) map[string]int {
+	counts := map[string]int{}
+	var tokens []string
+	flush := func() {
+		t.scan(tokens, func(id string) { counts[id]++ })
+		tokens = tokens[:0]
+	}
+
+	for _, word := range strings.Fields(s) {
+		word = strings.Trim(word, "()[]{}<>\"'`*|")
+		norm := Normalize(word)
+		if norm == "" {
+			continue
+		}
+
+		id, known := t.aliases[norm]
+		switch {
+		case strings.HasPrefix(word, "#"):
+			// un hashtag es un valor completo y corta cualquier frase
+			flush()
+			if known {
+				counts[id]++
+			}
+			continue
+		case known && !t.terms[id].Exact && strings.ContainsAny(norm, "./"):
+			// "node.js" o "ci/cd" no sobreviven a la tokenización
+			flush()
+			counts[id]++
+			continue
+		}
+
+		tokens = append(tokens, tokenize(norm)...)
+		// la puntuación final corta la frase
+		if strings.ContainsAny(word[len(word)-1:], ".,;:!?") {
+			flush()
+		}
+	}
+	flush()
+
+	return counts
+}
+
+// scan recorre tokens buscando el n-grama conocido más largo en cada
+// posición ("linux-from-scratch" antes que "linux") y llama a found con
+// cada coincidencia. Ignora los términos Exact.
+func (t *Taxonomy) scan(tokens []string, found func(id string)) {
+	for i := 0; i < len(tokens); {
+		matched := 0
+		for n := min(t.maxLen, len(tokens)-i); n > 0; n-- {
+			id, ok := t.aliases[strings.Join(tokens[i:i+n], "-")]
+			if !ok || t.terms[id].Exact {
+				continue
+			}
+			found(id)
+			matched = n
+			break
+		}
+		if matched == 0 {
+			matched = 1
+		}
+		i += matched
+	}
+}
+
+// Ancestors devuelve las categorías de id (sin incluirlo), de la más
+// cercana a la más general.
+func (t *Taxonomy) Ancestors(id string) []string {
+	lineage := t.lineage[id]
+	if len(lineage) <= 1 {
+		return nil
+	}
+	return lineage[1:]
+}
+
+// Roots devuelve las categorías raíz de id (las que no tienen padre),
+// incluido id si es raíz. Nil si id no es conocido.
+func (t *Taxonomy) Roots(id string) []string {
+	var roots []string
+	for _, ancestor := range t.lineage[id] {
+		if len(t.terms[ancestor].Parents) == 0 {
+			roots = append(roots, ancestor)
+		}
+	}
+	return roots
+}
+
+// IsA indica si value es category o pertenece a ella por jerarquía.
+// Un valor desconocido solo coincide consigo mismo.
+func (t *Taxonomy) IsA(value, category string) bool {
+	return t.matchWeight(value, t.canonicalOrNormalized(category)) > 0
+}
+
+// Weight devuelve el peso del término id (1 si no es conocido).
+func (t *Taxonomy) Weight(id string) float64 {
+	if term, ok := t.terms[id]; ok {
+		return term.Weight
+	}
+	return 1
+}
+
+// MatchWeight suma el peso de cada valor que pertenece a alguna de las
+// categorías. Cada valor cuenta una sola vez aunque coincida con varias.
+func (t *Taxonomy) MatchWeight(values, categories []string) float64 {
+	targets := make([]string, len(categories))
+	for i, c := range categories {
+		targets[i] = t.canonicalOrNormalized(c)
+	}
+
+	total := 0.0
+	for _, value := range values {
+		best := 0.0
+		for _, target := range targets {
+			if w := t.matchWeight(value, target); w > best {
+				best = w
+			}
+		}
+		total += best
+	}
+	return total
+}
+
+// matchWeight devuelve el peso con el que value coincide con target (0 si no).
+func (t *Taxonomy) matchWeight(value, target string) float64 {
+	ids := t.Resolve(value)
+	if len(ids) == 0 {
+		if Normalize(value) == target {
+			return 1
+		}
+		return 0
+	}
+
+	best := 0.0
+	for _, id := range ids {
+		for _, ancestor := range t.lineage[id] {
+			if ancestor == target && t.terms[id].Weight > best {
+				best = t.terms[id].Weight
+			}
+		}
+	}
+	return best
+}
+
+// canonicalOrNormalized devuelve el ID de s si existe, o su forma normalizada.
+func (t *Taxonomy) canonicalOrNormalized(s string) string {
+	if id, ok := t.Canonical(s); ok {
+		return id
+	}
+	return Normalize(s)
+}